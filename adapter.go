@@ -0,0 +1,100 @@
+package chidoc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"runtime"
+)
+
+// StatusCoder is implemented by errors returned by JSON handlers to set
+// the response status, other errors respond 500
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// JSONHandler a handler made by JSON, it holds the documentation of its
+// operation
+type JSONHandler struct {
+	op    *OperationBuilder
+	serve http.HandlerFunc
+}
+
+func (h *JSONHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r)
+}
+
+// Op continues the documentation of the operation, like OpHandler
+func (h *JSONHandler) Op() *OperationBuilder {
+	return h.op
+}
+
+// JSON adapts fn to a handler decoding the request body to Req and
+// encoding Resp, both are documented as the operation body and 200
+// response. The comment of fn documents the rest of the operation, a
+// struct{} Req has no body
+//
+//	router.Method(http.MethodPost, "/users", chidoc.JSON(CreateUser))
+func JSON[Req, Resp any](fn func(ctx context.Context, req Req) (Resp, error)) *JSONHandler {
+	var req Req
+	_, noBody := interface{}(req).(struct{})
+
+	serve := func(w http.ResponseWriter, r *http.Request) {
+		var req Req
+		if !noBody {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+				writeJSONError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		resp, err := fn(r.Context(), req)
+		if err != nil {
+			var status int = http.StatusInternalServerError
+			var coder StatusCoder
+			if errors.As(err, &coder) {
+				status = coder.StatusCode()
+			}
+
+			// messages of server errors may leak internal details
+			var message string = err.Error()
+			if status >= http.StatusInternalServerError {
+				message = http.StatusText(status)
+			}
+			writeJSONError(w, status, message)
+			return
+		}
+
+		var buffer bytes.Buffer
+		if err := json.NewEncoder(&buffer).Encode(resp); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+		w.Header().Set("Content-Type", mimeJSON)
+		w.Write(buffer.Bytes())
+	}
+
+	b := NewOp()
+	b.doc = runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	b.responses[http.StatusOK] = reflect.TypeOf((*Resp)(nil)).Elem()
+	if !noBody {
+		b.body = reflect.TypeOf((*Req)(nil)).Elem()
+	}
+	return &JSONHandler{op: b, serve: serve}
+}
+
+// jsonError body of the errors responded by JSON handlers
+type jsonError struct {
+	Error string `json:"error"`
+}
+
+// writeJSONError responds status with message as a jsonError
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", mimeJSON)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(jsonError{Error: message})
+}
@@ -0,0 +1,77 @@
+package chidoc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testNotFound struct{}
+
+func (testNotFound) Error() string   { return "post not found" }
+func (testNotFound) StatusCode() int { return http.StatusNotFound }
+
+type testEcho struct {
+	Text string `json:"text"`
+}
+
+func TestJSONHandler(t *testing.T) {
+	echo := JSON(func(ctx context.Context, req testEcho) (testEcho, error) {
+		switch req.Text {
+		case "missing":
+			return req, testNotFound{}
+		case "fail":
+			return req, errors.New("connection refused by 10.0.0.1")
+		}
+		return req, nil
+	})
+	unencodable := JSON(func(ctx context.Context, req struct{}) (func(), error) {
+		return func() {}, nil
+	})
+
+	tests := []struct {
+		handler http.Handler
+		body    string
+		status  int
+		want    string
+	}{
+		{echo, `{"text":"hi"}`, http.StatusOK, `{"text":"hi"}`},
+		{echo, `{"text":`, http.StatusBadRequest, `{"error":"unexpected EOF"}`},
+		{echo, `{"text":"missing"}`, http.StatusNotFound, `{"error":"post not found"}`},
+		{echo, `{"text":"fail"}`, http.StatusInternalServerError, `{"error":"Internal Server Error"}`},
+		{unencodable, ``, http.StatusInternalServerError, `{"error":"Internal Server Error"}`},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		tt.handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+
+		if w.Code != tt.status || strings.TrimSpace(w.Body.String()) != tt.want {
+			t.Errorf("%s: %d %s, want %d %s", tt.body, w.Code, w.Body, tt.status, tt.want)
+		}
+		if contentType := w.Header().Get("Content-Type"); contentType != mimeJSON {
+			t.Errorf("%s: Content-Type %q", tt.body, contentType)
+		}
+	}
+}
+
+func TestJSONHandlerOperation(t *testing.T) {
+	a := JSON(func(ctx context.Context, req testEcho) (testEcho, error) { return req, nil })
+	b := JSON(func(ctx context.Context, req testEcho) (testEcho, error) { return req, nil })
+
+	if OpHandler(a) != a.Op() {
+		t.Error("OpHandler returns another builder than Op")
+	}
+	if builder, exists := lookupOperation(b); !exists || builder != b.Op() {
+		t.Error("lookupOperation doesn't find the builder of b")
+	}
+	if a.Op() == b.Op() || sameHandler(a, b) {
+		t.Error("handlers of the same types share their operation")
+	}
+	if !sameHandler(a, a) {
+		t.Error("a is not the same handler as a")
+	}
+}
@@ -0,0 +1,33 @@
+package chidoc
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// builtinSchemas schemas of standard library and common types by typeKey,
+// they are overridden by MapType
+var builtinSchemas = map[string]Schema{
+	"time.Time":     {Type: "string", Format: "date-time"},
+	"time.Duration": {Type: "integer", Format: "int64"},
+	// the key of json.RawMessage depends on the Go version, it may be an
+	// alias of another type
+	typeKey(reflectModel{reflect.TypeOf(json.RawMessage{})}): {},
+	"net.IP":                      {Type: "string"},
+	"math/big.Int":                {Type: "integer"},
+	"github.com/google/uuid.UUID": {Type: "string", Format: "uuid"},
+	// encoding/json writes the fields of these types, they are documented
+	// as the uri and nullable primitives APIs usually encode them to
+	"net/url.URL":              {Type: "string", Format: "uri"},
+	"database/sql.NullString":  {Type: "string", Nullable: true},
+	"database/sql.NullBool":    {Type: "boolean", Nullable: true},
+	"database/sql.NullByte":    {Type: "integer", Nullable: true},
+	"database/sql.NullInt16":   {Type: "integer", Format: "int32", Nullable: true},
+	"database/sql.NullInt32":   {Type: "integer", Format: "int32", Nullable: true},
+	"database/sql.NullInt64":   {Type: "integer", Format: "int64", Nullable: true},
+	"database/sql.NullFloat64": {Type: "number", Format: "double", Nullable: true},
+	"database/sql.NullTime":    {Type: "string", Format: "date-time", Nullable: true},
+}
+
+// byteSchema schema of byte slices, encoded as base64 strings
+var byteSchema = Schema{Type: "string", Format: "byte"}
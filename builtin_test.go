@@ -0,0 +1,47 @@
+package chidoc
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testBuiltins struct {
+	Created time.Time       `json:"created"`
+	Raw     json.RawMessage `json:"raw"`
+	Link    url.URL         `json:"link"`
+	Name    sql.NullString  `json:"name"`
+	Count   sql.NullInt64   `json:"count"`
+	Data    []byte          `json:"data"`
+}
+
+func TestBuiltinSchemas(t *testing.T) {
+	defs := newDefinitions(nil)
+	defs.infer = true
+	if _, err := schemaOf(defs, reflect.TypeOf(testBuiltins{})); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]*Schema{
+		"created": {Type: "string", Format: "date-time"},
+		"raw":     {},
+		"link":    {Type: "string", Format: "uri"},
+		"name":    {Type: "string", Nullable: true},
+		"count":   {Type: "integer", Format: "int64", Nullable: true},
+		"data":    {Type: "string", Format: "byte"},
+	}
+	got := defs.schemas["testBuiltins"].Properties
+	for name, schema := range want {
+		if !reflect.DeepEqual(got[name], schema) {
+			t.Errorf("%s = %+v, want %+v", name, got[name], schema)
+		}
+	}
+
+	// sql.Null types encode null, they are not required
+	if required := defs.schemas["testBuiltins"].Required; !reflect.DeepEqual(required, []string{"created", "raw", "link", "data"}) {
+		t.Errorf("required = %v", required)
+	}
+}
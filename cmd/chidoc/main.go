@@ -0,0 +1,93 @@
+// Command chidoc extracts handler documentation at build time.
+//
+// Usage:
+//
+//	chidoc gen [-o chidoc_docs.go] [dir]
+//
+// gen writes a Go file registering the comments of the functions and
+// methods of the package in dir, so the docs are available to binaries
+// running without the source files. Use it from go:generate:
+//
+//	//go:generate go run github.com/n0bode/chidoc/cmd/chidoc gen
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/n0bode/chidoc"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: chidoc gen [-o file] [dir]")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "gen" {
+		usage()
+	}
+
+	flags := flag.NewFlagSet("gen", flag.ExitOnError)
+	output := flags.String("o", "chidoc_docs.go", "generated file, relative to dir")
+	flags.Usage = usage
+	flags.Parse(os.Args[2:])
+
+	var dir string = "."
+	if flags.NArg() > 0 {
+		dir = flags.Arg(0)
+	}
+
+	if err := gen(dir, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "chidoc:", err)
+		os.Exit(1)
+	}
+}
+
+// quote returns s as a Go string literal, raw when possible
+func quote(s string) string {
+	if strings.Contains(s, "`") || strings.Contains(s, "\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+func gen(dir, output string) error {
+	pkgName, pkgPath, comments, err := chidoc.ExtractDocs(dir)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(comments))
+	for name := range comments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buffer bytes.Buffer
+	fmt.Fprintln(&buffer, "// Code generated by chidoc gen. DO NOT EDIT.")
+	fmt.Fprintln(&buffer)
+	fmt.Fprintf(&buffer, "package %s\n\n", pkgName)
+	fmt.Fprintln(&buffer, `import "github.com/n0bode/chidoc"`)
+	fmt.Fprintln(&buffer)
+	fmt.Fprintln(&buffer, "func init() {")
+	fmt.Fprintf(&buffer, "chidoc.RegisterDocs(%s, map[string]string{\n", strconv.Quote(pkgPath))
+	for _, name := range names {
+		fmt.Fprintf(&buffer, "%s: %s,\n", strconv.Quote(name), quote(comments[name]))
+	}
+	fmt.Fprintln(&buffer, "})")
+	fmt.Fprintln(&buffer, "}")
+
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, output), source, 0644)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGen(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/items\n",
+		"items.go": "package items\n\n" +
+			"import \"net/http\"\n\n" +
+			"// ListItems lists `items`\n" +
+			"func ListItems(w http.ResponseWriter, r *http.Request) {}\n\n" +
+			"func undocumented(w http.ResponseWriter, r *http.Request) {}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := gen(dir, "docs.go"); err != nil {
+		t.Fatal(err)
+	}
+	source, err := os.ReadFile(filepath.Join(dir, "docs.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `package items
+
+import "github.com/n0bode/chidoc"
+
+func init() {
+	chidoc.RegisterDocs("example.com/items", map[string]string{
+		"example.com/items.ListItems": "ListItems lists ` + "`items`" + `\n",
+	})
+}
+`
+	if !strings.HasSuffix(string(source), want) {
+		t.Errorf("generated:\n%s\nwant suffix:\n%s", source, want)
+	}
+}
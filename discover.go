@@ -0,0 +1,159 @@
+package chidoc
+
+import (
+	"bufio"
+	"fmt"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// modulePath returns the import path of dir from the go.mod above it
+func modulePath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for root := abs; ; root = filepath.Dir(root) {
+		file, err := os.Open(filepath.Join(root, "go.mod"))
+		if err == nil {
+			defer file.Close()
+
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				var line string = strings.TrimSpace(scanner.Text())
+				if !strings.HasPrefix(line, "module") {
+					continue
+				}
+
+				var module string = strings.TrimSpace(strings.TrimPrefix(line, "module"))
+				if unquoted, err := strconv.Unquote(module); err == nil {
+					module = unquoted
+				}
+
+				rel, err := filepath.Rel(root, abs)
+				if err != nil {
+					return "", err
+				}
+				return path.Join(module, filepath.ToSlash(rel)), nil
+			}
+			return "", fmt.Errorf("%s has no module directive", file.Name())
+		}
+
+		if filepath.Dir(root) == root {
+			return "", fmt.Errorf("%s is not inside a module", dir)
+		}
+	}
+}
+
+// loadPackages type checks the Go packages of dirs. The package in
+// mainDir is checked as main, the path its types have at runtime
+func loadPackages(dirs []string, mainDir string) (pkgs []*types.Package, err error) {
+	var patterns []string
+	for _, dir := range dirs {
+		// relative directories, not import paths
+		abs, err := filepath.Abs(strings.TrimSuffix(dir, "/..."))
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(dir, "/...") {
+			abs += "/..."
+		}
+		patterns = append(patterns, abs)
+	}
+
+	conf := &packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedTypes | packages.NeedSyntax}
+	list, err := packages.Load(conf, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range list {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("discover %s: %w", pkg.PkgPath, pkg.Errors[0])
+		}
+		if len(pkg.GoFiles) == 0 {
+			continue
+		}
+
+		if pkg.Name != "main" || mainDir == "" || filepath.Dir(pkg.GoFiles[0]) != mainDir {
+			pkgs = append(pkgs, pkg.Types)
+			continue
+		}
+
+		// main packages are named main at runtime
+		conf := types.Config{Importer: importedPackages(pkg.Imports), IgnoreFuncBodies: true}
+		main, err := conf.Check("main", pkg.Fset, pkg.Syntax, nil)
+		if err != nil {
+			return nil, fmt.Errorf("discover %s: %w", pkg.PkgPath, err)
+		}
+		pkgs = append(pkgs, main)
+	}
+	return pkgs, nil
+}
+
+// importedPackages imports the packages loaded with a package
+type importedPackages map[string]*packages.Package
+
+func (imports importedPackages) Import(path string) (*types.Package, error) {
+	if pkg, exists := imports[path]; exists {
+		return pkg.Types, nil
+	}
+	return nil, fmt.Errorf("package %s is not imported", path)
+}
+
+// discoverDefinitions registers the named types of pkgs referenced by
+// paths that are not defined yet
+func discoverDefinitions(defs *definitions, pkgs []*types.Package, paths Paths) error {
+	root, err := decodeGeneric(paths)
+	if err != nil {
+		return err
+	}
+
+	missing := make(map[string]bool)
+	walkRefs("#", root, func(location, ref string) {
+		if !strings.HasPrefix(ref, schemaRef) {
+			return
+		}
+		var name string = strings.TrimPrefix(ref, schemaRef)
+		if _, exists := defs.schemas[name]; !exists {
+			missing[name] = true
+		}
+	})
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	for _, pkg := range pkgs {
+		scope := pkg.Scope()
+		for _, typeName := range scope.Names() {
+			obj, isType := scope.Lookup(typeName).(*types.TypeName)
+			if !isType || !obj.Exported() || !missing[defs.namer(pkg.Path(), typeName)] {
+				continue
+			}
+
+			name, err := defs.name(pkg.Path(), typeName)
+			if err != nil {
+				return err
+			}
+
+			s, err := parseModel(defs, &Schema{}, newCheckedModel(obj.Type()))
+			if err != nil {
+				return err
+			}
+
+			// only structs register themselves
+			if _, exists := defs.schemas[name]; !exists {
+				defs.schemas[name] = s
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package chidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// refPaths references the schemas of names from a response
+func refPaths(names ...string) Paths {
+	var paths = make(Paths)
+	for _, name := range names {
+		paths["/"+name] = &PathItem{Get: &Operation{Responses: Responses{
+			"200": {Description: "ok", Content: jsonContent(&Schema{Ref: schemaRef + name})},
+		}}}
+	}
+	return paths
+}
+
+func TestDiscoverDefinitions(t *testing.T) {
+	pkgs, err := loadPackages([]string{"testdata/discover/..."}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, pkg := range pkgs {
+		paths = append(paths, pkg.Path())
+	}
+	want := []string{"github.com/n0bode/chidoc/testdata/discover", "github.com/n0bode/chidoc/testdata/discover/cmd"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("packages = %v, want %v", paths, want)
+	}
+
+	defs := newDefinitions(nil)
+	if err := discoverDefinitions(defs, pkgs, refPaths("Item", "Order", "hidden")); err != nil {
+		t.Fatal(err)
+	}
+
+	item := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"id":    {Type: "integer"},
+			"tags":  {Type: "array", Items: &Schema{Ref: schemaRef + "Tag"}},
+			"price": {Type: "number"},
+		},
+	}
+	got, _ := json.Marshal(defs.schemas["Item"])
+	if want, _ := json.Marshal(item); !bytes.Equal(got, want) {
+		t.Errorf("Item = %s, want %s", got, want)
+	}
+	for _, name := range []string{"Tag", "Order"} {
+		if defs.schemas[name] == nil {
+			t.Errorf("%s is not discovered", name)
+		}
+	}
+	if defs.schemas["hidden"] != nil {
+		t.Error("unexported hidden is discovered")
+	}
+}
+
+func TestDiscoverMainPackage(t *testing.T) {
+	mainDir, err := filepath.Abs("testdata/discover/cmd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkgs, err := loadPackages([]string{"testdata/discover/cmd"}, mainDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 1 || pkgs[0].Path() != "main" {
+		t.Fatalf("packages = %v, want main", pkgs)
+	}
+
+	defs := newDefinitions(PackageNamer)
+	if err := discoverDefinitions(defs, pkgs, refPaths("main.Order")); err != nil {
+		t.Fatal(err)
+	}
+	if defs.schemas["main.Order"] == nil {
+		t.Errorf("main.Order is not discovered: %v", sortedKeys(defs.schemas))
+	}
+}
@@ -1,6 +1,7 @@
 package chidoc
 
 import (
+	"encoding/json"
 	"errors"
 	"strings"
 )
@@ -24,12 +25,25 @@ type DocSettings struct {
 	Render      DocRender
 	Theme       Theme
 
+	// strict fails generation when the document is not valid
+	strict bool
+
 	handlerIcon HandlerImage
 	handlerLogo HandlerImage
 
 	definitions []interface{}
+	namer       SchemaNamer
+	discover    []string
 	valuesPath  map[string]interface{}
 	auths       []Auth
+	anyMethods  []string
+	mounts      map[string]map[string]*OperationBuilder
+	deduper     OperationIDDeduper
+	infer       bool
+	allOf       bool
+
+	implementations map[string]*implementation
+	mapped          map[string]Schema
 }
 
 // NewDocSettings creates a new documentation settings
@@ -40,7 +54,11 @@ func NewDocSettings(title string, render DocRender) *DocSettings {
 		definitions: make([]interface{}, 0),
 		valuesPath:  make(map[string]interface{}),
 		auths:       make([]Auth, 0),
+		mounts:      make(map[string]map[string]*OperationBuilder),
 		Theme:       DefaultTheme,
+
+		implementations: make(map[string]*implementation),
+		mapped:          make(map[string]Schema),
 	}
 }
 
@@ -94,6 +112,41 @@ func (s *DocSettings) SetDefinitions(def ...interface{}) {
 	s.definitions = def
 }
 
+// DiscoverDefinitions registers models referenced from handler comments
+// and not set by SetDefinitions, they are looked up in the Go packages of
+// dirs, a dir ending with /... includes its subdirectories, by default ./...
+func (s *DocSettings) DiscoverDefinitions(dirs ...string) {
+	if len(dirs) == 0 {
+		dirs = []string{"./..."}
+	}
+	s.discover = dirs
+}
+
+// SetSchemaNamer set how models are named in components/schemas,
+// ShortNamer is used by default
+func (s *DocSettings) SetSchemaNamer(namer SchemaNamer) {
+	s.namer = namer
+}
+
+// SetOperationIDDeduper set how operationIds used by several operations
+// are renamed, SuffixDeduper is used by default
+func (s *DocSettings) SetOperationIDDeduper(deduper OperationIDDeduper) {
+	s.deduper = deduper
+}
+
+// SetInferFields infers model fields like encoding/json: fields without
+// pointer or omitempty are required, pointers and sql.Null types are
+// nullable and numbers with the string option are strings
+func (s *DocSettings) SetInferFields(infer bool) {
+	s.infer = infer
+}
+
+// SetEmbedAllOf documents embedded models with allOf, referencing them
+// instead of copying their fields
+func (s *DocSettings) SetEmbedAllOf(allOf bool) {
+	s.allOf = allOf
+}
+
 // SetBasePath set base path documention
 func (s *DocSettings) SetBasePath(basePath string) {
 	s.BasePath = basePath
@@ -104,6 +157,30 @@ func (s *DocSettings) SetAuths(auths ...Auth) {
 	s.auths = auths
 }
 
+// SetStrict validates the document on generation, see Validate
+func (s *DocSettings) SetStrict(strict bool) {
+	s.strict = strict
+}
+
+// SetAnyMethods sets the methods documented for routes serving any
+// method, like those of Handle, HandleFunc and Mount. Without methods they
+// are documented in the x-any-method extension of the path
+func (s *DocSettings) SetAnyMethods(methods ...string) {
+	s.anyMethods = methods
+}
+
+// SetMount documents method of the handler mounted at pattern, chi hides
+// mounted handlers behind its own. The method * documents any method
+//
+//	docSettings.SetMount("/static", "GET", chidoc.NewOp().Summary("static files"))
+func (s *DocSettings) SetMount(pattern, method string, b *OperationBuilder) {
+	pattern = strings.TrimSuffix(pattern, "/")
+	if s.mounts[pattern] == nil {
+		s.mounts[pattern] = make(map[string]*OperationBuilder)
+	}
+	s.mounts[pattern][strings.ToUpper(method)] = b
+}
+
 // SetTheme set colors and style
 func (s *DocSettings) SetTheme(theme Theme) {
 	s.Theme = theme
@@ -119,12 +196,20 @@ func (s *DocSettings) Get(name string) interface{} {
 	return s.valuesPath[name]
 }
 
-// Decode parse setting for openapi scruct
+// Decode sets the info of the settings, the openapi version and the
+// values set by path to a decoded openapi document
 func (s *DocSettings) Decode(ptr map[string]interface{}) (err error) {
-	s.Set("info.title", s.Title)
-	s.Set("info.description", s.Description)
-	s.Set("info.version", s.Version)
-	s.Set("openapi", "3.0.0")
+	info := map[string]interface{}{
+		"info.title":       s.Title,
+		"info.description": s.Description,
+		"info.version":     s.Version,
+		"openapi":          "3.0.0",
+	}
+	for path, value := range info {
+		if err = decodeSetPath(ptr, path, value); err != nil {
+			return err
+		}
+	}
 
 	for path, value := range s.valuesPath {
 		if err = decodeSetPath(ptr, path, value); err != nil {
@@ -133,3 +218,30 @@ func (s *DocSettings) Decode(ptr map[string]interface{}) (err error) {
 	}
 	return err
 }
+
+// apply sets values set by path over the generated document
+func (s *DocSettings) apply(doc *Document) error {
+	if len(s.valuesPath) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	raw := make(map[string]interface{})
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if err = s.Decode(raw); err != nil {
+		return err
+	}
+
+	if data, err = json.Marshal(raw); err != nil {
+		return err
+	}
+	*doc = Document{}
+	return json.Unmarshal(data, doc)
+}
@@ -0,0 +1,270 @@
+package chidoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// parseDocsTag parse the docs tag to keywords, keywords without value
+// are flags. The value starts after the first colon and ends at a comma
+// outside of brackets and braces, values in single quotes may have any
+// comma
+//
+//	docs:"required,description:Format: ISO 8601,pattern:^[a-z]{2,4}$"
+//	docs:"example:[1, 2],default:'a, b'"
+func parseDocsTag(tag string) map[string]string {
+	docs := make(map[string]string)
+	for tag != "" {
+		i := strings.IndexAny(tag, ":,")
+		if i == -1 {
+			i = len(tag)
+		}
+
+		var key string = strings.TrimSpace(tag[:i])
+		if i == len(tag) || tag[i] == ',' {
+			if key != "" {
+				docs[key] = key
+			}
+			tag = strings.TrimPrefix(tag[i:], ",")
+			continue
+		}
+
+		docs[key], tag = docsValue(tag[i+1:])
+	}
+	return docs
+}
+
+// docsValue reads a value of the docs tag, rest follows its comma
+func docsValue(tag string) (value, rest string) {
+	if quoted := strings.TrimLeft(tag, " "); strings.HasPrefix(quoted, "'") {
+		if end := strings.IndexByte(quoted[1:], '\''); end != -1 {
+			value, rest = quoted[1:end+1], quoted[end+2:]
+			if i := strings.IndexByte(rest, ','); i != -1 {
+				return value, rest[i+1:]
+			}
+			return value, ""
+		}
+	}
+
+	var depth int
+	for i := 0; i < len(tag); i++ {
+		switch tag[i] {
+		case '[', '{':
+			depth++
+		case ']', '}':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				return strings.TrimSpace(tag[:i]), tag[i+1:]
+			}
+		}
+	}
+	return strings.TrimSpace(tag), ""
+}
+
+// applyDocsTag sets the validation keywords of the docs tag to s, the
+// values are typed by the schema and must fit its type, the one defs has
+// for a $ref. Siblings of a $ref are ignored, so a referenced schema is
+// wrapped in allOf
+func applyDocsTag(defs *definitions, s *Schema, docs map[string]string) (*Schema, error) {
+	var typed *Schema = s
+	if s.Ref != "" {
+		typed = resolveRef(defs, s)
+		s = &Schema{Description: s.Description, AllOf: []*Schema{{Ref: s.Ref}}}
+	}
+
+	keys := make([]string, 0, len(docs))
+	for key := range docs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var raw string = strings.TrimSpace(docs[key])
+		if err := applyKeyword(s, typed, key, raw); err != nil {
+			return s, fmt.Errorf("docs %s: %w", key, err)
+		}
+	}
+
+	// nothing was set besides the reference
+	if len(s.AllOf) == 1 && reflect.DeepEqual(*s, Schema{AllOf: s.AllOf}) {
+		return s.AllOf[0], nil
+	}
+	return s, nil
+}
+
+// applyKeyword sets one keyword of the docs tag, flags have their key as
+// value. Example and default values are typed by typed, unknown keywords
+// are ignored
+func applyKeyword(s, typed *Schema, key, raw string) (err error) {
+	switch key {
+	case "required", "description", "enum":
+		// set by parseStruct
+	case "key":
+		if s.AdditionalProperties == nil {
+			return fitType(s, "map")
+		}
+		if s.AdditionalProperties.Schema == nil {
+			s.AdditionalProperties.Schema = &Schema{}
+		}
+		s.AdditionalProperties.Schema.Description = raw
+	case "len":
+		if err = fitType(s, "string"); err != nil {
+			return err
+		}
+		s.MinLength, s.MaxLength, err = parseLength(raw)
+	case "min", "max", "exclusiveMin", "exclusiveMax", "multipleOf":
+		if err = fitType(s, "integer", "number"); err != nil {
+			return err
+		}
+		err = applyNumber(s, key, raw)
+	case "minItems", "maxItems":
+		if err = fitType(s, "array"); err != nil {
+			return err
+		}
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		if key == "minItems" {
+			s.MinItems = &v
+		} else {
+			s.MaxItems = &v
+		}
+	case "uniqueItems":
+		if err = fitType(s, "array"); err != nil {
+			return err
+		}
+		s.UniqueItems, err = parseFlag(key, raw)
+	case "pattern":
+		if err = fitType(s, "string"); err != nil {
+			return err
+		}
+		s.Pattern = raw
+	case "format":
+		if err = fitType(s, "string", "integer", "number"); err != nil {
+			return err
+		}
+		s.Format = raw
+	case "example":
+		s.Example, err = typedValue(typed, raw)
+	case "default":
+		s.Default, err = typedValue(typed, raw)
+	case "deprecated":
+		s.Deprecated, err = parseFlag(key, raw)
+	case "readOnly":
+		s.ReadOnly, err = parseFlag(key, raw)
+	case "writeOnly":
+		s.WriteOnly, err = parseFlag(key, raw)
+	case "nullable":
+		// a wrapped $ref has no type
+		if s.AllOf == nil {
+			if err = fitType(s, "string", "integer", "number", "boolean", "array", "object"); err != nil {
+				return err
+			}
+		}
+		s.Nullable, err = parseFlag(key, raw)
+	}
+	return err
+}
+
+// applyNumber sets a numeric keyword, exclusiveMin and exclusiveMax may
+// have the bound as value or flag min and max
+func applyNumber(s *Schema, key, raw string) error {
+	if key == "exclusiveMin" || key == "exclusiveMax" {
+		if raw == key {
+			if key == "exclusiveMin" {
+				s.ExclusiveMinimum = true
+			} else {
+				s.ExclusiveMaximum = true
+			}
+			return nil
+		}
+	}
+
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return err
+	}
+
+	switch key {
+	case "min":
+		s.Minimum = &v
+	case "max":
+		s.Maximum = &v
+	case "exclusiveMin":
+		s.Minimum, s.ExclusiveMinimum = &v, true
+	case "exclusiveMax":
+		s.Maximum, s.ExclusiveMaximum = &v, true
+	case "multipleOf":
+		if v <= 0 {
+			return fmt.Errorf("%v is not greater than 0", v)
+		}
+		s.MultipleOf = &v
+	}
+	return nil
+}
+
+// parseFlag parse a boolean keyword, set without value it's true
+func parseFlag(key, raw string) (bool, error) {
+	if raw == key {
+		return true, nil
+	}
+	return strconv.ParseBool(raw)
+}
+
+// typedValue parse a value of the schema type, arrays and objects are
+// JSON. The value must be one of the schema enum
+func typedValue(s *Schema, raw string) (v interface{}, err error) {
+	if v, err = parseTyped(s, raw); err != nil || len(s.Enum) == 0 {
+		return v, err
+	}
+
+	// compared by JSON, enum values may be any Go number type
+	encoded, _ := json.Marshal(v)
+	for _, value := range s.Enum {
+		if enum, _ := json.Marshal(value); string(enum) == string(encoded) {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not one of the enum values", raw)
+}
+
+// parseTyped parse a value of the schema type
+func parseTyped(s *Schema, raw string) (v interface{}, err error) {
+	switch s.Type {
+	case "integer":
+		return strconv.ParseInt(raw, 10, 64)
+	case "number":
+		return strconv.ParseFloat(raw, 64)
+	case "boolean":
+		return strconv.ParseBool(raw)
+	case "string":
+		return raw, nil
+	case "":
+		return nil, fitType(s, "")
+	}
+
+	err = json.Unmarshal([]byte(raw), &v)
+	return v, err
+}
+
+// fitType fails when the schema type is not one of types
+func fitType(s *Schema, types ...string) error {
+	for _, t := range types {
+		if s.Type == t && t != "" {
+			return nil
+		}
+	}
+
+	if s.Type == "" {
+		return fmt.Errorf("does not fit a referenced or untyped schema")
+	}
+	return fmt.Errorf("does not fit type %s", s.Type)
+}
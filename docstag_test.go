@@ -0,0 +1,146 @@
+package chidoc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDocsTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want map[string]string
+	}{
+		{"", map[string]string{}},
+		{"required", map[string]string{"required": "required"}},
+		{"description: full name ,required", map[string]string{"description": "full name", "required": "required"}},
+		{"description:Format: ISO 8601", map[string]string{"description": "Format: ISO 8601"}},
+		{"example:2024-01-01T10:00:00Z", map[string]string{"example": "2024-01-01T10:00:00Z"}},
+		{"pattern:^[a-z]{2,4}$,len:2-4", map[string]string{"pattern": "^[a-z]{2,4}$", "len": "2-4"}},
+		{`example:[1, 2],default:{"a":1,"b":2}`, map[string]string{"example": "[1, 2]", "default": `{"a":1,"b":2}`}},
+		{"description:'a, b',uniqueItems", map[string]string{"description": "a, b", "uniqueItems": "uniqueItems"}},
+		{"description:it's a name,required", map[string]string{"description": "it's a name", "required": "required"}},
+		{"required,,", map[string]string{"required": "required"}},
+	}
+
+	for _, tt := range tests {
+		if got := parseDocsTag(tt.tag); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseDocsTag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestApplyDocsTag(t *testing.T) {
+	floatPtr := func(v float64) *float64 { return &v }
+	uintPtr := func(v uint64) *uint64 { return &v }
+
+	tests := []struct {
+		tag     string
+		schema  Schema
+		want    Schema
+		wantErr bool
+	}{
+		{
+			tag:    "required,description:name",
+			schema: Schema{Type: "string"},
+			want:   Schema{Type: "string"},
+		},
+		{
+			tag:    "len:2-64,pattern:^[a-z]+$,format:email",
+			schema: Schema{Type: "string"},
+			want:   Schema{Type: "string", MinLength: uintPtr(2), MaxLength: uintPtr(64), Pattern: "^[a-z]+$", Format: "email"},
+		},
+		{
+			tag:    "len:5",
+			schema: Schema{Type: "string"},
+			want:   Schema{Type: "string", MinLength: uintPtr(5), MaxLength: uintPtr(5)},
+		},
+		{
+			tag:    "min:0,max:130,example:42,default:18",
+			schema: Schema{Type: "integer"},
+			want:   Schema{Type: "integer", Minimum: floatPtr(0), Maximum: floatPtr(130), Example: int64(42), Default: int64(18)},
+		},
+		{
+			tag:    "exclusiveMin:0,exclusiveMax,max:1,multipleOf:0.5",
+			schema: Schema{Type: "number"},
+			want:   Schema{Type: "number", Minimum: floatPtr(0), ExclusiveMinimum: true, Maximum: floatPtr(1), ExclusiveMaximum: true, MultipleOf: floatPtr(0.5)},
+		},
+		{
+			tag:    "minItems:1,maxItems:3,uniqueItems,example:[1, 2]",
+			schema: Schema{Type: "array"},
+			want:   Schema{Type: "array", MinItems: uintPtr(1), MaxItems: uintPtr(3), UniqueItems: true, Example: []interface{}{float64(1), float64(2)}},
+		},
+		{
+			tag:    "deprecated,readOnly,writeOnly:false,nullable",
+			schema: Schema{Type: "boolean"},
+			want:   Schema{Type: "boolean", Deprecated: true, ReadOnly: true, Nullable: true},
+		},
+		{
+			tag:    "key:user id",
+			schema: Schema{Type: "object", AdditionalProperties: &AdditionalProperties{Allowed: true}},
+			want:   Schema{Type: "object", AdditionalProperties: &AdditionalProperties{Allowed: true, Schema: &Schema{Description: "user id"}}},
+		},
+		{
+			tag:    "deprecated",
+			schema: Schema{Ref: schemaRef + "User", Description: "the author"},
+			want:   Schema{Description: "the author", Deprecated: true, AllOf: []*Schema{{Ref: schemaRef + "User"}}},
+		},
+		{
+			tag:    "required",
+			schema: Schema{Ref: schemaRef + "User"},
+			want:   Schema{Ref: schemaRef + "User"},
+		},
+		{tag: "len:10-2", schema: Schema{Type: "string"}, wantErr: true},
+		{tag: "min:1", schema: Schema{Type: "string"}, wantErr: true},
+		{tag: "example:one", schema: Schema{Type: "integer"}, wantErr: true},
+		{tag: "multipleOf:0", schema: Schema{Type: "number"}, wantErr: true},
+		{tag: "uniqueItems", schema: Schema{Type: "string"}, wantErr: true},
+		{tag: "key:id", schema: Schema{Type: "string"}, wantErr: true},
+		{
+			tag:    `example:{"name": "ana"}`,
+			schema: Schema{Ref: schemaRef + "User"},
+			want:   Schema{Example: map[string]interface{}{"name": "ana"}, AllOf: []*Schema{{Ref: schemaRef + "User"}}},
+		},
+		{
+			tag:    "example:green,description:the color",
+			schema: Schema{Ref: schemaRef + "ColorEnum", Description: "the color"},
+			want:   Schema{Description: "the color", Example: "green", AllOf: []*Schema{{Ref: schemaRef + "ColorEnum"}}},
+		},
+		{
+			tag:    "default:2",
+			schema: Schema{Ref: schemaRef + "LevelEnum"},
+			want:   Schema{Default: int64(2), AllOf: []*Schema{{Ref: schemaRef + "LevelEnum"}}},
+		},
+		{
+			// unknown keywords are ignored
+			tag:    "minimum:1",
+			schema: Schema{Type: "integer"},
+			want:   Schema{Type: "integer"},
+		},
+		{tag: "example:1", schema: Schema{Ref: schemaRef + "Missing"}, wantErr: true},
+		{tag: "default:blue", schema: Schema{Ref: schemaRef + "ColorEnum"}, wantErr: true},
+		{tag: "example:3", schema: Schema{Ref: schemaRef + "LevelEnum"}, wantErr: true},
+		{tag: "example:one", schema: Schema{Ref: schemaRef + "LevelEnum"}, wantErr: true},
+	}
+
+	defs := newDefinitions(nil)
+	defs.schemas["User"] = &Schema{Type: "object"}
+	defs.schemas["ColorEnum"] = Enum("Color", "", "red", "green").Schema()
+	defs.schemas["LevelEnum"] = Enum("Level", "", 1, 2).Schema()
+
+	for _, tt := range tests {
+		schema := tt.schema
+		got, err := applyDocsTag(defs, &schema, parseDocsTag(tt.tag))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("applyDocsTag(%q) on %s succeeded, want error", tt.tag, tt.schema.Type)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("applyDocsTag(%q): %v", tt.tag, err)
+		} else if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("applyDocsTag(%q) = %+v, want %+v", tt.tag, *got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,741 @@
+package chidoc
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Document is the root object of an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI      string                `json:"openapi"`
+	Info         Info                  `json:"info"`
+	Servers      []Server              `json:"servers,omitempty"`
+	Paths        Paths                 `json:"paths"`
+	Components   *Components           `json:"components,omitempty"`
+	Security     []SecurityRequirement `json:"security,omitempty"`
+	Tags         []*Tag                `json:"tags,omitempty"`
+	ExternalDocs *ExternalDocs         `json:"externalDocs,omitempty"`
+	Extensions   Extensions            `json:"-"`
+}
+
+// Info metadata about the API
+type Info struct {
+	Title          string     `json:"title"`
+	Description    string     `json:"description,omitempty"`
+	TermsOfService string     `json:"termsOfService,omitempty"`
+	Contact        *Contact   `json:"contact,omitempty"`
+	License        *License   `json:"license,omitempty"`
+	Version        string     `json:"version"`
+	Extensions     Extensions `json:"-"`
+}
+
+// Contact information for the exposed API
+type Contact struct {
+	Name       string     `json:"name,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	Email      string     `json:"email,omitempty"`
+	Extensions Extensions `json:"-"`
+}
+
+// License information for the exposed API
+type License struct {
+	Name       string     `json:"name"`
+	URL        string     `json:"url,omitempty"`
+	Extensions Extensions `json:"-"`
+}
+
+// ServerVariable a variable for server URL template substitution
+type ServerVariable struct {
+	Enum        []string   `json:"enum,omitempty"`
+	Default     string     `json:"default"`
+	Description string     `json:"description,omitempty"`
+	Extensions  Extensions `json:"-"`
+}
+
+// Paths relative paths to the endpoints and their operations
+type Paths map[string]*PathItem
+
+// PathItem operations available on a single path
+type PathItem struct {
+	Ref         string       `json:"$ref,omitempty"`
+	Summary     string       `json:"summary,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Get         *Operation   `json:"get,omitempty"`
+	Put         *Operation   `json:"put,omitempty"`
+	Post        *Operation   `json:"post,omitempty"`
+	Delete      *Operation   `json:"delete,omitempty"`
+	Options     *Operation   `json:"options,omitempty"`
+	Head        *Operation   `json:"head,omitempty"`
+	Patch       *Operation   `json:"patch,omitempty"`
+	Trace       *Operation   `json:"trace,omitempty"`
+	Servers     []Server     `json:"servers,omitempty"`
+	Parameters  []*Parameter `json:"parameters,omitempty"`
+	Extensions  Extensions   `json:"-"`
+}
+
+// Operation a single API operation on a path
+type Operation struct {
+	Tags         []string              `json:"tags,omitempty"`
+	Summary      string                `json:"summary,omitempty"`
+	Description  string                `json:"description,omitempty"`
+	ExternalDocs *ExternalDocs         `json:"externalDocs,omitempty"`
+	OperationID  string                `json:"operationId,omitempty"`
+	Parameters   []*Parameter          `json:"parameters,omitempty"`
+	RequestBody  *RequestBody          `json:"requestBody,omitempty"`
+	Responses    Responses             `json:"responses,omitempty"`
+	Callbacks    map[string]Callback   `json:"callbacks,omitempty"`
+	Deprecated   bool                  `json:"deprecated,omitempty"`
+	Security     []SecurityRequirement `json:"security,omitempty"`
+	Servers      []Server              `json:"servers,omitempty"`
+	Extensions   Extensions            `json:"-"`
+
+	// generatedID the operationId was not set by the comment or code
+	generatedID bool
+}
+
+// ExternalDocs reference to external documentation
+type ExternalDocs struct {
+	Description string     `json:"description,omitempty"`
+	URL         string     `json:"url"`
+	Extensions  Extensions `json:"-"`
+}
+
+// Parameter a single operation parameter
+type Parameter struct {
+	Ref             string                `json:"$ref,omitempty"`
+	Name            string                `json:"name,omitempty"`
+	In              string                `json:"in,omitempty"`
+	Description     string                `json:"description,omitempty"`
+	Required        bool                  `json:"required,omitempty"`
+	Deprecated      bool                  `json:"deprecated,omitempty"`
+	AllowEmptyValue bool                  `json:"allowEmptyValue,omitempty"`
+	Style           string                `json:"style,omitempty"`
+	Explode         *bool                 `json:"explode,omitempty"`
+	AllowReserved   bool                  `json:"allowReserved,omitempty"`
+	Schema          *Schema               `json:"schema,omitempty"`
+	Example         interface{}           `json:"example,omitempty"`
+	Examples        map[string]*Example   `json:"examples,omitempty"`
+	Content         map[string]*MediaType `json:"content,omitempty"`
+	Extensions      Extensions            `json:"-"`
+}
+
+// Header a response or encoding header, same as parameter without name and in
+type Header struct {
+	Ref             string                `json:"$ref,omitempty"`
+	Description     string                `json:"description,omitempty"`
+	Required        bool                  `json:"required,omitempty"`
+	Deprecated      bool                  `json:"deprecated,omitempty"`
+	AllowEmptyValue bool                  `json:"allowEmptyValue,omitempty"`
+	Style           string                `json:"style,omitempty"`
+	Explode         *bool                 `json:"explode,omitempty"`
+	AllowReserved   bool                  `json:"allowReserved,omitempty"`
+	Schema          *Schema               `json:"schema,omitempty"`
+	Example         interface{}           `json:"example,omitempty"`
+	Examples        map[string]*Example   `json:"examples,omitempty"`
+	Content         map[string]*MediaType `json:"content,omitempty"`
+	Extensions      Extensions            `json:"-"`
+}
+
+// RequestBody a single request body
+type RequestBody struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Extensions  Extensions            `json:"-"`
+}
+
+// MediaType schema and examples for a media type
+type MediaType struct {
+	Schema     *Schema              `json:"schema,omitempty"`
+	Example    interface{}          `json:"example,omitempty"`
+	Examples   map[string]*Example  `json:"examples,omitempty"`
+	Encoding   map[string]*Encoding `json:"encoding,omitempty"`
+	Extensions Extensions           `json:"-"`
+}
+
+// Encoding encoding of a single schema property
+type Encoding struct {
+	ContentType   string             `json:"contentType,omitempty"`
+	Headers       map[string]*Header `json:"headers,omitempty"`
+	Style         string             `json:"style,omitempty"`
+	Explode       *bool              `json:"explode,omitempty"`
+	AllowReserved bool               `json:"allowReserved,omitempty"`
+	Extensions    Extensions         `json:"-"`
+}
+
+// Responses expected responses of an operation by status code
+type Responses map[string]*Response
+
+// Response a single response from an operation
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+	Links       map[string]*Link      `json:"links,omitempty"`
+	Extensions  Extensions            `json:"-"`
+}
+
+// Callback possible out-of-band requests by expression
+type Callback map[string]*PathItem
+
+// Example an example value
+type Example struct {
+	Ref           string      `json:"$ref,omitempty"`
+	Summary       string      `json:"summary,omitempty"`
+	Description   string      `json:"description,omitempty"`
+	Value         interface{} `json:"value,omitempty"`
+	ExternalValue string      `json:"externalValue,omitempty"`
+	Extensions    Extensions  `json:"-"`
+}
+
+// Link a possible design-time link for a response
+type Link struct {
+	Ref          string                 `json:"$ref,omitempty"`
+	OperationRef string                 `json:"operationRef,omitempty"`
+	OperationID  string                 `json:"operationId,omitempty"`
+	Parameters   map[string]interface{} `json:"parameters,omitempty"`
+	RequestBody  interface{}            `json:"requestBody,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	Server       *Server                `json:"server,omitempty"`
+	Extensions   Extensions             `json:"-"`
+}
+
+// Tag metadata of a tag used by operations
+type Tag struct {
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+	Extensions   Extensions    `json:"-"`
+}
+
+// Components reusable objects of the document
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	Responses       map[string]*Response       `json:"responses,omitempty"`
+	Parameters      map[string]*Parameter      `json:"parameters,omitempty"`
+	Examples        map[string]*Example        `json:"examples,omitempty"`
+	RequestBodies   map[string]*RequestBody    `json:"requestBodies,omitempty"`
+	Headers         map[string]*Header         `json:"headers,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+	Links           map[string]*Link           `json:"links,omitempty"`
+	Callbacks       map[string]Callback        `json:"callbacks,omitempty"`
+	Extensions      Extensions                 `json:"-"`
+}
+
+// Schema a data type, it's a subset of JSON Schema
+type Schema struct {
+	Ref                  string                `json:"$ref,omitempty"`
+	Title                string                `json:"title,omitempty"`
+	Description          string                `json:"description,omitempty"`
+	Type                 string                `json:"type,omitempty"`
+	Format               string                `json:"format,omitempty"`
+	Enum                 []interface{}         `json:"enum,omitempty"`
+	Default              interface{}           `json:"default,omitempty"`
+	Example              interface{}           `json:"example,omitempty"`
+	MultipleOf           *float64              `json:"multipleOf,omitempty"`
+	Maximum              *float64              `json:"maximum,omitempty"`
+	ExclusiveMaximum     bool                  `json:"exclusiveMaximum,omitempty"`
+	Minimum              *float64              `json:"minimum,omitempty"`
+	ExclusiveMinimum     bool                  `json:"exclusiveMinimum,omitempty"`
+	MaxLength            *uint64               `json:"maxLength,omitempty"`
+	MinLength            *uint64               `json:"minLength,omitempty"`
+	Pattern              string                `json:"pattern,omitempty"`
+	MaxItems             *uint64               `json:"maxItems,omitempty"`
+	MinItems             *uint64               `json:"minItems,omitempty"`
+	UniqueItems          bool                  `json:"uniqueItems,omitempty"`
+	MaxProperties        *uint64               `json:"maxProperties,omitempty"`
+	MinProperties        *uint64               `json:"minProperties,omitempty"`
+	Required             []string              `json:"required,omitempty"`
+	Items                *Schema               `json:"items,omitempty"`
+	AllOf                []*Schema             `json:"allOf,omitempty"`
+	OneOf                []*Schema             `json:"oneOf,omitempty"`
+	AnyOf                []*Schema             `json:"anyOf,omitempty"`
+	Not                  *Schema               `json:"not,omitempty"`
+	Properties           map[string]*Schema    `json:"properties,omitempty"`
+	AdditionalProperties *AdditionalProperties `json:"additionalProperties,omitempty"`
+	// PropertyNames schema of the keys, from JSON Schema
+	PropertyNames *Schema        `json:"propertyNames,omitempty"`
+	Nullable      bool           `json:"nullable,omitempty"`
+	Discriminator *Discriminator `json:"discriminator,omitempty"`
+	ReadOnly      bool           `json:"readOnly,omitempty"`
+	WriteOnly     bool           `json:"writeOnly,omitempty"`
+	XML           *XML           `json:"xml,omitempty"`
+	ExternalDocs  *ExternalDocs  `json:"externalDocs,omitempty"`
+	Deprecated    bool           `json:"deprecated,omitempty"`
+	Extensions    Extensions     `json:"-"`
+}
+
+// AdditionalProperties value of a schema additionalProperties, which is
+// either a schema or a boolean
+type AdditionalProperties struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// Discriminator hint about the schema of a polymorphic payload
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+// XML metadata for XML representation of a property
+type XML struct {
+	Name       string     `json:"name,omitempty"`
+	Namespace  string     `json:"namespace,omitempty"`
+	Prefix     string     `json:"prefix,omitempty"`
+	Attribute  bool       `json:"attribute,omitempty"`
+	Wrapped    bool       `json:"wrapped,omitempty"`
+	Extensions Extensions `json:"-"`
+}
+
+// SecurityScheme a security scheme used by the operations
+type SecurityScheme struct {
+	Ref              string      `json:"$ref,omitempty"`
+	Type             AuthType    `json:"type,omitempty"`
+	Description      string      `json:"description,omitempty"`
+	Name             string      `json:"name,omitempty"`
+	In               InType      `json:"in,omitempty"`
+	Scheme           string      `json:"scheme,omitempty"`
+	BearerFormat     string      `json:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows `json:"flows,omitempty"`
+	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty"`
+	Extensions       Extensions  `json:"-"`
+}
+
+// OAuthFlows configuration of the supported OAuth flows
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+	Extensions        Extensions `json:"-"`
+}
+
+// OAuthFlow configuration of a single OAuth flow
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	RefreshURL       string            `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
+	Extensions       Extensions        `json:"-"`
+}
+
+// SecurityRequirement required security schemes by name with their scopes
+type SecurityRequirement map[string][]string
+
+// Extensions specification extensions, keys must begin with x-
+type Extensions map[string]interface{}
+
+// Operations returns operations of the path by lower case method
+func (p *PathItem) Operations() map[string]*Operation {
+	ops := make(map[string]*Operation)
+	for method, op := range map[string]*Operation{
+		"get":     p.Get,
+		"put":     p.Put,
+		"post":    p.Post,
+		"delete":  p.Delete,
+		"options": p.Options,
+		"head":    p.Head,
+		"patch":   p.Patch,
+		"trace":   p.Trace,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+// SetOperation sets the operation for method, returns false when the
+// method is not supported by OpenAPI
+func (p *PathItem) SetOperation(method string, op *Operation) bool {
+	switch strings.ToLower(method) {
+	case "get":
+		p.Get = op
+	case "put":
+		p.Put = op
+	case "post":
+		p.Post = op
+	case "delete":
+		p.Delete = op
+	case "options":
+		p.Options = op
+	case "head":
+		p.Head = op
+	case "patch":
+		p.Patch = op
+	case "trace":
+		p.Trace = op
+	default:
+		return false
+	}
+	return true
+}
+
+// MarshalJSON encodes the schema, or the boolean when it has no schema
+func (a AdditionalProperties) MarshalJSON() ([]byte, error) {
+	if a.Schema != nil {
+		return json.Marshal(a.Schema)
+	}
+	return json.Marshal(a.Allowed)
+}
+
+// UnmarshalJSON decodes a schema or a boolean
+func (a *AdditionalProperties) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		a.Schema = nil
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+// marshalExtensions encodes v and adds the extensions as fields
+func marshalExtensions(v interface{}, ext Extensions) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil || len(ext) == 0 {
+		return raw, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	for key, value := range ext {
+		if !strings.HasPrefix(key, "x-") {
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = data
+	}
+	return json.Marshal(fields)
+}
+
+// unmarshalExtensions decodes data into v and returns fields beginning
+// with x-
+func unmarshalExtensions(data []byte, v interface{}) (ext Extensions, err error) {
+	if err = json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for key, value := range fields {
+		if !strings.HasPrefix(key, "x-") {
+			continue
+		}
+		if ext == nil {
+			ext = make(Extensions)
+		}
+		ext[key] = value
+	}
+	return ext, nil
+}
+
+// MarshalJSON encodes the document with its extensions
+func (d Document) MarshalJSON() ([]byte, error) {
+	type document Document
+	return marshalExtensions(document(d), d.Extensions)
+}
+
+// UnmarshalJSON decodes the document with its extensions
+func (d *Document) UnmarshalJSON(data []byte) (err error) {
+	type document Document
+	d.Extensions, err = unmarshalExtensions(data, (*document)(d))
+	return err
+}
+
+// MarshalJSON encodes the info with its extensions
+func (i Info) MarshalJSON() ([]byte, error) {
+	type info Info
+	return marshalExtensions(info(i), i.Extensions)
+}
+
+// UnmarshalJSON decodes the info with its extensions
+func (i *Info) UnmarshalJSON(data []byte) (err error) {
+	type info Info
+	i.Extensions, err = unmarshalExtensions(data, (*info)(i))
+	return err
+}
+
+// MarshalJSON encodes the path item with its extensions
+func (p PathItem) MarshalJSON() ([]byte, error) {
+	type pathItem PathItem
+	return marshalExtensions(pathItem(p), p.Extensions)
+}
+
+// UnmarshalJSON decodes the path item with its extensions
+func (p *PathItem) UnmarshalJSON(data []byte) (err error) {
+	type pathItem PathItem
+	p.Extensions, err = unmarshalExtensions(data, (*pathItem)(p))
+	return err
+}
+
+// MarshalJSON encodes the operation with its extensions
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+	return marshalExtensions(operation(o), o.Extensions)
+}
+
+// UnmarshalJSON decodes the operation with its extensions
+func (o *Operation) UnmarshalJSON(data []byte) (err error) {
+	type operation Operation
+	o.Extensions, err = unmarshalExtensions(data, (*operation)(o))
+	return err
+}
+
+// MarshalJSON encodes the schema with its extensions
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type schema Schema
+	return marshalExtensions(schema(s), s.Extensions)
+}
+
+// UnmarshalJSON decodes the schema with its extensions
+func (s *Schema) UnmarshalJSON(data []byte) (err error) {
+	type schema Schema
+	s.Extensions, err = unmarshalExtensions(data, (*schema)(s))
+	return err
+}
+
+// MarshalJSON encodes the contact with its extensions
+func (c Contact) MarshalJSON() ([]byte, error) {
+	type contact Contact
+	return marshalExtensions(contact(c), c.Extensions)
+}
+
+// UnmarshalJSON decodes the contact with its extensions
+func (c *Contact) UnmarshalJSON(data []byte) (err error) {
+	type contact Contact
+	c.Extensions, err = unmarshalExtensions(data, (*contact)(c))
+	return err
+}
+
+// MarshalJSON encodes the license with its extensions
+func (l License) MarshalJSON() ([]byte, error) {
+	type license License
+	return marshalExtensions(license(l), l.Extensions)
+}
+
+// UnmarshalJSON decodes the license with its extensions
+func (l *License) UnmarshalJSON(data []byte) (err error) {
+	type license License
+	l.Extensions, err = unmarshalExtensions(data, (*license)(l))
+	return err
+}
+
+// MarshalJSON encodes the server variable with its extensions
+func (s ServerVariable) MarshalJSON() ([]byte, error) {
+	type serverVariable ServerVariable
+	return marshalExtensions(serverVariable(s), s.Extensions)
+}
+
+// UnmarshalJSON decodes the server variable with its extensions
+func (s *ServerVariable) UnmarshalJSON(data []byte) (err error) {
+	type serverVariable ServerVariable
+	s.Extensions, err = unmarshalExtensions(data, (*serverVariable)(s))
+	return err
+}
+
+// MarshalJSON encodes the external docs with its extensions
+func (e ExternalDocs) MarshalJSON() ([]byte, error) {
+	type externalDocs ExternalDocs
+	return marshalExtensions(externalDocs(e), e.Extensions)
+}
+
+// UnmarshalJSON decodes the external docs with its extensions
+func (e *ExternalDocs) UnmarshalJSON(data []byte) (err error) {
+	type externalDocs ExternalDocs
+	e.Extensions, err = unmarshalExtensions(data, (*externalDocs)(e))
+	return err
+}
+
+// MarshalJSON encodes the parameter with its extensions
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	type parameter Parameter
+	return marshalExtensions(parameter(p), p.Extensions)
+}
+
+// UnmarshalJSON decodes the parameter with its extensions
+func (p *Parameter) UnmarshalJSON(data []byte) (err error) {
+	type parameter Parameter
+	p.Extensions, err = unmarshalExtensions(data, (*parameter)(p))
+	return err
+}
+
+// MarshalJSON encodes the header with its extensions
+func (h Header) MarshalJSON() ([]byte, error) {
+	type header Header
+	return marshalExtensions(header(h), h.Extensions)
+}
+
+// UnmarshalJSON decodes the header with its extensions
+func (h *Header) UnmarshalJSON(data []byte) (err error) {
+	type header Header
+	h.Extensions, err = unmarshalExtensions(data, (*header)(h))
+	return err
+}
+
+// MarshalJSON encodes the request body with its extensions
+func (r RequestBody) MarshalJSON() ([]byte, error) {
+	type requestBody RequestBody
+	return marshalExtensions(requestBody(r), r.Extensions)
+}
+
+// UnmarshalJSON decodes the request body with its extensions
+func (r *RequestBody) UnmarshalJSON(data []byte) (err error) {
+	type requestBody RequestBody
+	r.Extensions, err = unmarshalExtensions(data, (*requestBody)(r))
+	return err
+}
+
+// MarshalJSON encodes the media type with its extensions
+func (m MediaType) MarshalJSON() ([]byte, error) {
+	type mediaType MediaType
+	return marshalExtensions(mediaType(m), m.Extensions)
+}
+
+// UnmarshalJSON decodes the media type with its extensions
+func (m *MediaType) UnmarshalJSON(data []byte) (err error) {
+	type mediaType MediaType
+	m.Extensions, err = unmarshalExtensions(data, (*mediaType)(m))
+	return err
+}
+
+// MarshalJSON encodes the encoding with its extensions
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	type encoding Encoding
+	return marshalExtensions(encoding(e), e.Extensions)
+}
+
+// UnmarshalJSON decodes the encoding with its extensions
+func (e *Encoding) UnmarshalJSON(data []byte) (err error) {
+	type encoding Encoding
+	e.Extensions, err = unmarshalExtensions(data, (*encoding)(e))
+	return err
+}
+
+// MarshalJSON encodes the response with its extensions
+func (r Response) MarshalJSON() ([]byte, error) {
+	type response Response
+	return marshalExtensions(response(r), r.Extensions)
+}
+
+// UnmarshalJSON decodes the response with its extensions
+func (r *Response) UnmarshalJSON(data []byte) (err error) {
+	type response Response
+	r.Extensions, err = unmarshalExtensions(data, (*response)(r))
+	return err
+}
+
+// MarshalJSON encodes the example with its extensions
+func (e Example) MarshalJSON() ([]byte, error) {
+	type example Example
+	return marshalExtensions(example(e), e.Extensions)
+}
+
+// UnmarshalJSON decodes the example with its extensions
+func (e *Example) UnmarshalJSON(data []byte) (err error) {
+	type example Example
+	e.Extensions, err = unmarshalExtensions(data, (*example)(e))
+	return err
+}
+
+// MarshalJSON encodes the link with its extensions
+func (l Link) MarshalJSON() ([]byte, error) {
+	type link Link
+	return marshalExtensions(link(l), l.Extensions)
+}
+
+// UnmarshalJSON decodes the link with its extensions
+func (l *Link) UnmarshalJSON(data []byte) (err error) {
+	type link Link
+	l.Extensions, err = unmarshalExtensions(data, (*link)(l))
+	return err
+}
+
+// MarshalJSON encodes the tag with its extensions
+func (t Tag) MarshalJSON() ([]byte, error) {
+	type tag Tag
+	return marshalExtensions(tag(t), t.Extensions)
+}
+
+// UnmarshalJSON decodes the tag with its extensions
+func (t *Tag) UnmarshalJSON(data []byte) (err error) {
+	type tag Tag
+	t.Extensions, err = unmarshalExtensions(data, (*tag)(t))
+	return err
+}
+
+// MarshalJSON encodes the components with its extensions
+func (c Components) MarshalJSON() ([]byte, error) {
+	type components Components
+	return marshalExtensions(components(c), c.Extensions)
+}
+
+// UnmarshalJSON decodes the components with its extensions
+func (c *Components) UnmarshalJSON(data []byte) (err error) {
+	type components Components
+	c.Extensions, err = unmarshalExtensions(data, (*components)(c))
+	return err
+}
+
+// MarshalJSON encodes the XML with its extensions
+func (x XML) MarshalJSON() ([]byte, error) {
+	type xml XML
+	return marshalExtensions(xml(x), x.Extensions)
+}
+
+// UnmarshalJSON decodes the XML with its extensions
+func (x *XML) UnmarshalJSON(data []byte) (err error) {
+	type xml XML
+	x.Extensions, err = unmarshalExtensions(data, (*xml)(x))
+	return err
+}
+
+// MarshalJSON encodes the security scheme with its extensions
+func (s SecurityScheme) MarshalJSON() ([]byte, error) {
+	type securityScheme SecurityScheme
+	return marshalExtensions(securityScheme(s), s.Extensions)
+}
+
+// UnmarshalJSON decodes the security scheme with its extensions
+func (s *SecurityScheme) UnmarshalJSON(data []byte) (err error) {
+	type securityScheme SecurityScheme
+	s.Extensions, err = unmarshalExtensions(data, (*securityScheme)(s))
+	return err
+}
+
+// MarshalJSON encodes the OAuth flows with its extensions
+func (o OAuthFlows) MarshalJSON() ([]byte, error) {
+	type oauthFlows OAuthFlows
+	return marshalExtensions(oauthFlows(o), o.Extensions)
+}
+
+// UnmarshalJSON decodes the OAuth flows with its extensions
+func (o *OAuthFlows) UnmarshalJSON(data []byte) (err error) {
+	type oauthFlows OAuthFlows
+	o.Extensions, err = unmarshalExtensions(data, (*oauthFlows)(o))
+	return err
+}
+
+// MarshalJSON encodes the OAuth flow with its extensions
+func (o OAuthFlow) MarshalJSON() ([]byte, error) {
+	type oauthFlow OAuthFlow
+	return marshalExtensions(oauthFlow(o), o.Extensions)
+}
+
+// UnmarshalJSON decodes the OAuth flow with its extensions
+func (o *OAuthFlow) UnmarshalJSON(data []byte) (err error) {
+	type oauthFlow OAuthFlow
+	o.Extensions, err = unmarshalExtensions(data, (*oauthFlow)(o))
+	return err
+}
@@ -0,0 +1,79 @@
+package chidoc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyKeepsExtensions(t *testing.T) {
+	ext := func(value string) Extensions {
+		return Extensions{"x-test": value}
+	}
+
+	doc := &Document{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "test", Version: "1", Contact: &Contact{Name: "api", Extensions: ext("contact")}},
+		Servers: []Server{{URL: "/", Extensions: ext("server")}},
+		Paths: Paths{"/a": {Get: &Operation{
+			Parameters:  []*Parameter{{In: "query", Name: "q", Extensions: ext("parameter")}},
+			RequestBody: &RequestBody{Content: map[string]*MediaType{mimeJSON: {Extensions: ext("media")}}},
+			Responses:   Responses{"200": {Description: "ok", Extensions: ext("response")}},
+		}}},
+		Components: &Components{
+			SecuritySchemes: map[string]*SecurityScheme{"key": {Type: "apiKey", Extensions: ext("scheme")}},
+			Extensions:      ext("components"),
+		},
+		Tags: []*Tag{{Name: "API", Extensions: ext("tag")}},
+	}
+
+	settings := NewDocSettings("applied", RedocRender)
+	settings.Set("info.x-logo.url", "/logo.png")
+	if err := settings.apply(doc); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]Extensions{
+		"contact":    doc.Info.Contact.Extensions,
+		"server":     doc.Servers[0].Extensions,
+		"parameter":  doc.Paths["/a"].Get.Parameters[0].Extensions,
+		"media":      doc.Paths["/a"].Get.RequestBody.Content[mimeJSON].Extensions,
+		"response":   doc.Paths["/a"].Get.Responses["200"].Extensions,
+		"scheme":     doc.Components.SecuritySchemes["key"].Extensions,
+		"components": doc.Components.Extensions,
+		"tag":        doc.Tags[0].Extensions,
+	}
+	for value, extensions := range got {
+		if !reflect.DeepEqual(extensions, ext(value)) {
+			t.Errorf("%s extensions = %v", value, extensions)
+		}
+	}
+
+	// Decode sets the info of the settings
+	if doc.Info.Title != "applied" || doc.Info.Extensions["x-logo"] == nil {
+		t.Errorf("info = %+v", doc.Info)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	settings := NewDocSettings("title", RedocRender)
+	settings.Version = "2"
+	settings.Set("info.x-logo.url", "/logo.png")
+
+	raw := make(map[string]interface{})
+	if err := settings.Decode(raw); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":       "title",
+			"description": "",
+			"version":     "2",
+			"x-logo":      map[string]interface{}{"url": "/logo.png"},
+		},
+	}
+	if !reflect.DeepEqual(raw, want) {
+		t.Errorf("Decode() = %v, want %v", raw, want)
+	}
+}
@@ -19,6 +19,8 @@ func Enum(name, description string, values ...interface{}) StructEnum {
 }
 
 // Parse converts structEnum to dict(enum)
+//
+// Deprecated: use Schema
 func (s StructEnum) Parse() map[string]interface{} {
 	m := make(map[string]interface{})
 	m["type"] = s.Type
@@ -26,3 +28,12 @@ func (s StructEnum) Parse() map[string]interface{} {
 	m["description"] = s.Description
 	return m
 }
+
+// Schema converts structEnum to schema(enum)
+func (s StructEnum) Schema() *Schema {
+	return &Schema{
+		Type:        s.Type,
+		Enum:        s.Enum,
+		Description: s.Description,
+	}
+}
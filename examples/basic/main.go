@@ -88,7 +88,7 @@ var data []User = []User{
 //    content:
 //     application/json:
 //      schema:
-//       "$ref": "#/components/schemes/Response"
+//       "$ref": "#/components/schemas/Response"
 func GETSay(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(Response{
 		Message: "Hi! don't worry, it's running",
@@ -135,13 +135,13 @@ func GetChildrenByID(w http.ResponseWriter, r *http.Request) {
 //    content:
 //     application/json:
 //      schema:
-//       "$ref": "#/components/schemes/User"
+//       "$ref": "#/components/schemas/User"
 //  '400':
 //    description: User is dead
 //    content:
 //     application/json:
 //      schema:
-//       "$ref": "#/components/schemes/Response"
+//       "$ref": "#/components/schemas/Response"
 func GETUserByID(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "userID")
 	age, _ := strconv.ParseInt(userID, 10, 32)
@@ -176,7 +176,7 @@ func main() {
 	// Here, you set up models, that you gonna use documention, like
 	/*
 	  schema:
-	   "$ref": "#/components/schemes/HTTPResponse"
+	   "$ref": "#/components/schemas/HTTPResponse"
 	*/
 	docSettings.SetDefinitions(Response{}, User{}, chidoc.Enum("Weapons", `
 	1 - Hammer
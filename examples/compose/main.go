@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
@@ -49,18 +50,14 @@ func HTTPError(w http.ResponseWriter, message string, status int) {
 // responses:
 //  '200':
 //    description: Returns users by id
-//    content:
-//     application/json:
-//      schema:
-//       type: array
-//       items:
-//        "$ref": "#/components/schemes/UserOrm"
-func GetAllUsers(conn *db.DB) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		HTTPSuccess(w, conn.Filter(func(user db.UserOrm) (db.UserOrm, bool) {
-			user.Password = ""
-			return user, false
-		}), 200)
+func GetAllUsers(conn *db.DB) func(ctx context.Context, req struct{}) (Response, error) {
+	return func(ctx context.Context, req struct{}) (Response, error) {
+		return Response{
+			Data: conn.Filter(func(user db.UserOrm) (db.UserOrm, bool) {
+				user.Password = ""
+				return user, false
+			}),
+		}, nil
 	}
 }
 
@@ -74,14 +71,14 @@ func GetAllUsers(conn *db.DB) http.HandlerFunc {
 //    content:
 //     application/json:
 //      schema:
-//       "$ref": "#/components/schemes/UserOrm"
+//       "$ref": "#/components/schemas/UserOrm"
 // requestBody:
 //  description: Optional description in *Markdown*
 //  required: true
 //  content:
 //   application/json:
 //    schema:
-//     $ref: '#/components/schemes/User'
+//     $ref: '#/components/schemas/User'
 func PostUser(conn *db.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var user User
@@ -118,20 +115,20 @@ func PostUser(conn *db.DB) http.HandlerFunc {
 //    content:
 //     application/json:
 //      schema:
-//       "$ref": "#/components/schemes/UserOrm"
+//       "$ref": "#/components/schemas/UserOrm"
 //  '400':
 //    description: Check message field response
 //    content:
 //     application/json:
 //      schema:
-//       "$ref": "#/components/schemes/Response"
+//       "$ref": "#/components/schemas/Response"
 // requestBody:
 //  description: Optional description in *Markdown*
 //  required: true
 //  content:
 //   application/json:
 //    schema:
-//     $ref: '#/components/schemes/User'
+//     $ref: '#/components/schemas/User'
 func PutUser(conn *db.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -202,7 +199,7 @@ func Routes() *chi.Mux {
 	router := chi.NewRouter()
 
 	// ... API
-	router.Get("/users", GetAllUsers(db))
+	router.Method(http.MethodGet, "/users", chidoc.JSON(GetAllUsers(db)))
 	router.Post("/users", PostUser(db))
 	router.Put("/users/{id:[0-9]+}", PutUser(db))
 	router.Post("/token", PostToken(db))
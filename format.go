@@ -0,0 +1,52 @@
+package chidoc
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// mimeYAML content type used to serve the spec as YAML
+	mimeYAML = "text/x-yaml"
+	// mimeJSON content type used to serve the spec as JSON
+	mimeJSON = "application/json"
+)
+
+// mimeAliases maps accepted media types to the content type served
+var mimeAliases = map[string]string{
+	"application/json":   mimeJSON,
+	"application/yaml":   mimeYAML,
+	"application/x-yaml": mimeYAML,
+	"text/yaml":          mimeYAML,
+	"text/x-yaml":        mimeYAML,
+}
+
+// negotiateMime picks the spec content type from an Accept header,
+// YAML is used when the header is empty or nothing matches
+func negotiateMime(accept string) string {
+	var best string = mimeYAML
+	var bestQ float64 = -1
+
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		mime := strings.ToLower(strings.TrimSpace(fields[0]))
+
+		var q float64 = 1
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+				q = v
+			}
+		}
+
+		found, exists := mimeAliases[mime]
+		if !exists || q <= 0 || q <= bestQ {
+			continue
+		}
+		best, bestQ = found, q
+	}
+	return best
+}
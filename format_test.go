@@ -0,0 +1,27 @@
+package chidoc
+
+import "testing"
+
+func TestNegotiateMime(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", mimeYAML},
+		{"application/json", mimeJSON},
+		{"Application/JSON", mimeJSON},
+		{"application/yaml", mimeYAML},
+		{"text/html, application/json", mimeJSON},
+		{"application/json;q=0.5, text/yaml", mimeYAML},
+		{"application/json;q=0.9, text/yaml;q=0.8", mimeJSON},
+		{"application/json;q=0", mimeYAML},
+		{"text/html", mimeYAML},
+		{"*/*", mimeYAML},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateMime(tt.accept); got != tt.want {
+			t.Errorf("negotiateMime(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
@@ -2,18 +2,21 @@ package chidoc
 
 import (
 	"bytes"
+	"encoding"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"go/ast"
-	"go/parser"
-	"go/token"
 	"image/png"
 	"io"
 	"net/http"
+	"os"
 	"reflect"
+	"regexp/syntax"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
-	"time"
 	"unicode"
 
 	"github.com/ghodss/yaml"
@@ -115,127 +118,299 @@ func replaceHTML(html, title, path string, settings *DocSettings) string {
 	return r.Replace(html)
 }
 
-func splitFuncName(name string) string {
-	var arr []string = strings.Split(name, ".")
+const (
+	// schemaRef prefix to reference a model
+	schemaRef = "#/components/schemas/"
+	// legacySchemaRef prefix older versions used to reference a model
+	legacySchemaRef = "#/components/schemes/"
+)
 
-	for i := len(arr) - 1; i >= 0; i-- {
-		var fname string = arr[i]
-		if !strings.HasPrefix(fname, "func") {
-			return strings.Split(fname, "-")[0]
-		}
-	}
-	return ""
+func infoFunc(funcPC *runtime.Func) (fn funcName, filename string, line int) {
+	filename, line = funcPC.FileLine(funcPC.Entry())
+	return parseFuncName(funcPC.Name()), filename, line
 }
 
-func infoFunc(handler http.Handler) (name, filename string, line int) {
-	valueOf := reflect.ValueOf(handler)
-	funcPC := runtime.FuncForPC(valueOf.Pointer())
-	filename, line = funcPC.FileLine(0)
-	return splitFuncName(funcPC.Name()), filename, line
+func routeDescription(handler http.Handler, src *sources) (description *Operation, err error) {
+	return funcDescription(handlerFunc(handler), src)
 }
 
-func routeDescription(handler http.Handler, tmp map[string][]*ast.CommentGroup) (description map[string]interface{}, err error) {
-	fname, filename, _ := infoFunc(handler)
+// funcDescription parses the operation in the comment of a function
+func funcDescription(funcPC *runtime.Func, src *sources) (description *Operation, err error) {
+	fn, filename, line := infoFunc(funcPC)
 
 	// default tag API
-	description = make(map[string]interface{})
-	description["tags"] = []string{"API"}
+	description = &Operation{Tags: []string{"API"}}
 
-	comments, exists := tmp[filename]
-	if !exists {
-		fset := token.NewFileSet()
-		parse, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	var data string
+	if comment, registered := lookupDocs(fn); registered {
+		// extracted at build time by chidoc gen
+		if comment != "" {
+			data = "#" + comment
+		}
+	} else {
+		group, err := sourceDoc(src, fn, filename, line)
 		if err != nil {
 			return description, err
 		}
-		tmp[filename] = parse.Comments
-		comments = parse.Comments
-	}
 
-	var data string
-	for _, group := range comments {
-		if !strings.HasPrefix(group.Text(), fname) {
-			continue
+		if group != nil {
+			data = "#" + group.Text()
 		}
-		data = "#" + group.Text()
-		break
 	}
 
 	if data == "" {
 		return description, nil
 	}
 
-	if err := yaml.Unmarshal([]byte(data), &description); err != nil {
+	// comments written for older versions reference models at schemes
+	data = strings.ReplaceAll(data, legacySchemaRef, schemaRef)
+
+	description = &Operation{}
+	if err := yaml.Unmarshal([]byte(data), description); err != nil {
 		return description, err
 	}
 
-	if description != nil {
-		if _, exists := description["tags"]; !exists {
-			description["tags"] = []string{"API"}
-		}
+	if len(description.Tags) == 0 {
+		description.Tags = []string{"API"}
 	}
 	return description, nil
 }
 
-func parseRoutePattern(pattern string) (path string, params []PathArg) {
-	params = make([]PathArg, 0)
+// hasDoc checks if the declaration of funcPC has a comment
+func hasDoc(funcPC *runtime.Func, src *sources) bool {
+	fn, filename, line := infoFunc(funcPC)
+	if comment, registered := lookupDocs(fn); registered {
+		return comment != ""
+	}
 
-	/*if strings.Contains(pattern[1:], "/") {
-		return pattern, params
-	}*/
+	group, err := sourceDoc(src, fn, filename, line)
+	return err == nil && group != nil
+}
 
-	for _, subName := range strings.Split(pattern[1:], "/") {
-		if len(subName) == 0 || subName[0] != '{' || subName[len(subName)-1] != '}' {
-			path += "/" + subName
-			continue
+// sourceDoc returns the comment of fn from its source
+func sourceDoc(src *sources, fn funcName, filename string, line int) (*ast.CommentGroup, error) {
+	// method values are wrapped, their declaration is in the package
+	var files []*ast.File
+	var err error
+	if filename == "<autogenerated>" {
+		files, err = src.pkg(fn.pkgPath)
+	} else {
+		var file *ast.File
+		if file, err = src.parse(filename); err == nil {
+			files = []*ast.File{file}
 		}
+	}
 
-		var index int = strings.Index(subName, ":")
-		if index < 0 {
-			path += "/" + subName
-			continue
+	// binaries built with -trimpath or run without their sources have no
+	// comments to read, like handlers of other modules
+	if errors.Is(err, errNoSource) || errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return src.funcDoc(files, fn, filename, line)
+}
+
+// parseRoutePattern replaces the chi placeholders of pattern, like
+// {id:[0-9]+}, by OpenAPI path templates and returns their parameters
+func parseRoutePattern(pattern string) (path string, params []*Parameter) {
+	params = make([]*Parameter, 0)
+
+	for {
+		var start int = strings.IndexByte(pattern, '{')
+		if start < 0 {
+			break
 		}
 
-		var name string = subName[1:index]
-		var format string = subName[index+1 : len(subName)-1]
+		// regexes may have braces, like {code:[a-z]{2}}
+		var depth, end int
+		for end = start; end < len(pattern); end++ {
+			if pattern[end] == '{' {
+				depth++
+			} else if pattern[end] == '}' {
+				depth--
+			}
+			if depth == 0 {
+				break
+			}
+		}
+		if end == len(pattern) {
+			break
+		}
+
+		var name, regex string = pattern[start+1 : end], ""
+		if index := strings.IndexByte(name, ':'); index >= 0 {
+			name, regex = name[:index], name[index+1:]
+		}
 
-		params = append(params, PathArg{
+		params = append(params, &Parameter{
 			In:       "path",
 			Name:     name,
 			Required: true,
-			Schema: PathArgType{
-				Kind:   "string",
-				Format: format,
-			},
+			Schema:   patternSchema(regex),
 		})
 
-		path += "/{" + name + "}"
+		path += pattern[:start] + "{" + name + "}"
+		pattern = pattern[end+1:]
 	}
-	return path, params
+	return path + pattern, params
 }
 
-func appendPathParams(d map[string]interface{}, params []PathArg) (re []interface{}) {
-	re = make([]interface{}, 0)
+// patternSchema returns the schema of a placeholder regex, digits are
+// integers, UUIDs are strings with uuid format, others are patterns
+func patternSchema(regex string) *Schema {
+	if regex == "" {
+		return &Schema{Type: "string"}
+	}
 
-	for _, param := range params {
-		re = append(re, param)
+	// chi matches the whole segment, a pattern matches anywhere
+	var anchored string = "^(?:" + regex + ")$"
+	re, err := syntax.Parse(regex, syntax.Perl)
+	if err != nil {
+		return &Schema{Type: "string", Pattern: anchored}
 	}
 
-	parameters, exists := d["parameters"]
-	if !exists {
-		return re
+	if isDigitsRegex(re.Simplify()) {
+		return &Schema{Type: "integer"}
 	}
 
-	arr, isArr := parameters.([]interface{})
-	if !isArr {
-		return re
+	if groups, ok := hexGroups(re, []int{0}); ok && reflect.DeepEqual(groups, uuidGroups) {
+		return &Schema{Type: "string", Format: "uuid"}
 	}
+	return &Schema{Type: "string", Pattern: anchored}
+}
+
+// isDigitsRegex reports whether re only matches digits
+func isDigitsRegex(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+		return true
+	case syntax.OpLiteral, syntax.OpCharClass:
+		for _, r := range re.Rune {
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+		return len(re.Rune) > 0
+	case syntax.OpCapture, syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat, syntax.OpConcat, syntax.OpAlternate:
+		var digits bool
+		for _, sub := range re.Sub {
+			if !isDigitsRegex(sub) {
+				return false
+			}
+			digits = digits || (sub.Op != syntax.OpBeginLine && sub.Op != syntax.OpEndLine &&
+				sub.Op != syntax.OpBeginText && sub.Op != syntax.OpEndText)
+		}
+		return digits
+	}
+	return false
+}
 
-	re = append(re, arr...)
+// uuidGroups the hex digits of each dash separated group of a UUID
+var uuidGroups []int = []int{8, 4, 4, 4, 12}
+
+// hexGroups counts the hex digits of each dash separated group matched by
+// re, ok is false when re matches anything else or a variable length
+func hexGroups(re *syntax.Regexp, groups []int) ([]int, bool) {
+	switch re.Op {
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+		return groups, true
+	case syntax.OpCapture:
+		return hexGroups(re.Sub[0], groups)
+	case syntax.OpConcat:
+		var ok bool = true
+		for _, sub := range re.Sub {
+			if groups, ok = hexGroups(sub, groups); !ok {
+				return nil, false
+			}
+		}
+		return groups, true
+	case syntax.OpRepeat:
+		if re.Min != re.Max {
+			return nil, false
+		}
+		if isHexClass(re.Sub[0]) {
+			groups[len(groups)-1] += re.Min
+			return groups, true
+		}
+
+		var ok bool = true
+		for i := 0; i < re.Min; i++ {
+			if groups, ok = hexGroups(re.Sub[0], groups); !ok {
+				return nil, false
+			}
+		}
+		return groups, true
+	case syntax.OpCharClass:
+		if isHexClass(re) {
+			groups[len(groups)-1]++
+			return groups, true
+		}
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if r != '-' {
+				return nil, false
+			}
+			groups = append(groups, 0)
+		}
+		return groups, true
+	}
+	return nil, false
+}
+
+// isHexClass reports whether re is a class of the hex digits, like
+// [0-9a-f], [0-9A-F] or [0-9a-fA-F]
+func isHexClass(re *syntax.Regexp) bool {
+	if re.Op != syntax.OpCharClass {
+		return false
+	}
+
+	var digits, lower, upper int
+	for i := 0; i+1 < len(re.Rune); i += 2 {
+		for r := re.Rune[i]; r <= re.Rune[i+1]; r++ {
+			switch {
+			case r >= '0' && r <= '9':
+				digits++
+			case r >= 'a' && r <= 'f':
+				lower++
+			case r >= 'A' && r <= 'F':
+				upper++
+			default:
+				return false
+			}
+		}
+	}
+	return digits == 10 && (lower == 6 || upper == 6)
+}
+
+// appendPathParams adds the parameters of the route pattern not declared
+// by the operation
+func appendPathParams(d *Operation, params []*Parameter) (re []*Parameter) {
+	re = make([]*Parameter, 0)
+	for _, param := range params {
+		var declared bool
+		for _, p := range d.Parameters {
+			declared = declared || (p != nil && p.In == param.In && p.Name == param.Name)
+		}
+		if !declared {
+			re = append(re, param)
+		}
+	}
+	re = append(re, d.Parameters...)
 	return re
 }
 
-func walkRoute(parent string, p map[string]interface{}, parseTMP map[string][]*ast.CommentGroup, r chi.Routes) (map[string]interface{}, error) {
+// wildcardParam documents the chi wildcard at the end of a route
+var wildcardParam = Parameter{
+	In:          "path",
+	Name:        "wildcard",
+	Description: "Rest of the path, it may contain slashes",
+	Required:    true,
+	Schema:      &Schema{Type: "string"},
+}
+
+func walkRoute(parent string, p Paths, settings *DocSettings, src *sources, defs *definitions, r chi.Routes) (Paths, error) {
 	for _, route := range r.Routes() {
 		var rawPath string = parent + route.Pattern
 		path, params := parseRoutePattern(parent + route.Pattern)
@@ -247,36 +422,161 @@ func walkRoute(parent string, p map[string]interface{}, parseTMP map[string][]*a
 		if strings.HasSuffix(path, "/*") {
 			path = path[:len(path)-2]
 			rawPath = rawPath[:len(rawPath)-2]
+
+			// catch-all routes match the rest of the path
+			if route.SubRoutes == nil {
+				param := wildcardParam
+				path += "/{" + param.Name + "}"
+				params = append(params, &param)
+			}
 		}
 
 		//var path string = parent + pattern
 		if route.SubRoutes == nil {
-			doc := make(map[string]interface{})
-			for method, handler := range route.Handlers {
-				if method == "" {
+			item, exists := p[path]
+			if !exists {
+				item = &PathItem{}
+			}
+
+			// Handle, HandleFunc and Mount store their handler under * and
+			// every method
+			anyHandler := route.Handlers["*"]
+			for _, method := range sortedKeys(route.Handlers) {
+				var handler http.Handler = route.Handlers[method]
+				if method == "" || method == "*" {
+					continue
+				}
+				if anyHandler != nil && sameHandler(handler, anyHandler) {
 					continue
 				}
 
-				d, err := routeDescription(handler, parseTMP)
+				d, err := handlerOperation(handler, src, defs)
 				if err != nil {
 					return nil, err
 				}
 
 				// add parameters
-				if params != nil {
-					d["parameters"] = appendPathParams(d, params)
+				if d != nil {
+					d.Parameters = appendPathParams(d, params)
+					item.SetOperation(method, d)
+				}
+			}
+
+			if anyHandler != nil {
+				ops, err := anyMethodOperations(rawPath, anyHandler, settings, src, defs)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, method := range sortedKeys(ops) {
+					var d *Operation = ops[method]
+					d.Parameters = appendPathParams(d, params)
+					setAnyMethod(item, method, settings.anyMethods, d)
 				}
-				doc[strings.ToLower(method)] = d
 			}
-			p[path] = doc
+
+			if len(item.Operations()) > 0 || len(item.Extensions) > 0 {
+				p[path] = item
+			}
 			continue
 		}
-		walkRoute(rawPath, p, parseTMP, route.SubRoutes)
+
+		if _, err := walkRoute(rawPath, p, settings, src, defs, route.SubRoutes); err != nil {
+			return nil, err
+		}
 	}
 
 	return p, nil
 }
 
+// handlerOperation documents a handler, nil for the handlers of the
+// documentation. Operations built from code are preferred over comments
+func handlerOperation(handler http.Handler, src *sources, defs *definitions) (*Operation, error) {
+	// document the endpoint, not its middlewares
+	handler = unwrapHandler(handler)
+	if isDocHandler(handler) {
+		return nil, nil
+	}
+
+	var d *Operation
+	var err error
+	if builder, exists := lookupOperation(handler); exists {
+		d, err = builder.build(defs, src)
+	} else {
+		d, err = routeDescription(handler, src)
+	}
+
+	if err == nil && d.OperationID == "" {
+		d.OperationID = operationName(handler, src)
+		d.generatedID = true
+	}
+	return d, err
+}
+
+// sortedKeys returns the keys of m in order, building operations in a
+// fixed order registers the models the same way on every run
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// anyMethodOperations documents the handler of a route serving any method
+// by method, * for any. Mounted handlers are documented by SetMount
+func anyMethodOperations(pattern string, handler http.Handler, settings *DocSettings, src *sources, defs *definitions) (map[string]*Operation, error) {
+	ops := make(map[string]*Operation)
+	if !isMountHandler(handler) {
+		d, err := handlerOperation(handler, src, defs)
+		if d != nil {
+			ops["*"] = d
+		}
+		return ops, err
+	}
+
+	builders, exists := settings.mounts[strings.TrimSuffix(pattern, "/")]
+	if !exists {
+		ops["*"] = &Operation{Tags: []string{"API"}}
+		return ops, nil
+	}
+
+	for _, method := range sortedKeys(builders) {
+		d, err := builders[method].build(defs, src)
+		if err != nil {
+			return nil, err
+		}
+		ops[method] = d
+	}
+	return ops, nil
+}
+
+// setAnyMethod sets the operation of method, * sets it for the methods
+// not set in anyMethods, or in x-any-method without anyMethods
+func setAnyMethod(item *PathItem, method string, anyMethods []string, d *Operation) {
+	if method != "*" {
+		item.SetOperation(method, d)
+		return
+	}
+
+	if len(anyMethods) == 0 {
+		if item.Extensions == nil {
+			item.Extensions = make(Extensions)
+		}
+		item.Extensions["x-any-method"] = d
+		return
+	}
+
+	ops := item.Operations()
+	for _, m := range anyMethods {
+		if _, exists := ops[strings.ToLower(m)]; !exists {
+			op := *d
+			item.SetOperation(m, &op)
+		}
+	}
+}
+
 // parseTag parse format docs:"description: TITLE,required" to
 // map[string]string
 // 	descritption: " TITLE"
@@ -311,32 +611,43 @@ func parseTag(tag string) (m map[string]string) {
 	return
 }
 
-// isIntType checks if type is a interger
-func isIntType(t reflect.Type) bool {
-	return t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64
+// isIntType checks if kind is a interger
+func isIntType(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Uint64
+}
+
+// isFloatType checks if kind is a number
+func isFloatType(k reflect.Kind) bool {
+	return k >= reflect.Float32 && k <= reflect.Float64
 }
 
-// isFloatType checks if type is a number
-func isFloatType(t reflect.Type) bool {
-	return t.Kind() >= reflect.Float32 && t.Kind() <= reflect.Float64
+// textMarshalerType map keys encoded by their text
+var textMarshalerType reflect.Type = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// intKeySchema schema of the integer keys of a map, encoded as strings
+func intKeySchema(unsigned bool) *Schema {
+	if unsigned {
+		return &Schema{Type: "string", Pattern: "^[0-9]+$"}
+	}
+	return &Schema{Type: "string", Pattern: "^-?[0-9]+$"}
 }
 
-// isArrType checks if type is a arr
-func isArrType(t reflect.Type) bool {
-	return t.Kind() == reflect.Array || t.Kind() == reflect.Slice
+// isArrType checks if kind is a arr
+func isArrType(k reflect.Kind) bool {
+	return k == reflect.Array || k == reflect.Slice
 }
 
 func typeName(obj interface{}) string {
 	t := reflect.TypeOf(obj)
 
 	switch {
-	case isIntType(t):
+	case isIntType(t.Kind()):
 		return "integer"
-	case isFloatType(t):
+	case isFloatType(t.Kind()):
 		return "number"
 	case t.Kind() == reflect.Bool:
 		return "boolean"
-	case isArrType(t):
+	case isArrType(t.Kind()):
 		return "array"
 	case t.Kind() == reflect.Struct:
 		return "object"
@@ -344,179 +655,426 @@ func typeName(obj interface{}) string {
 	return "string"
 }
 
+// parseLength parse docs len "5" or "5-10" to min and max length
+func parseLength(length string) (min, max *uint64, err error) {
+	var lower, upper string = length, length
+	if index := strings.IndexByte(length, '-'); index != -1 {
+		lower, upper = length[:index], length[index+1:]
+	}
+
+	minLength, err := strconv.ParseUint(strings.TrimSpace(lower), 10, 64)
+	if err != nil {
+		return nil, nil, err
+	}
+	maxLength, err := strconv.ParseUint(strings.TrimSpace(upper), 10, 64)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if minLength > maxLength {
+		return nil, nil, fmt.Errorf("min length %d is greater than max length %d", minLength, maxLength)
+	}
+	return &minLength, &maxLength, nil
+}
+
 // parseDefinitions parse definition models for a map[Type]
-func parseDefinition(schemes, m map[string]interface{}, t reflect.Type) map[string]interface{} {
+func parseDefinition(defs *definitions, m *Schema, t reflect.Type) (*Schema, error) {
+	return parseModel(defs, m, reflectModel{t})
+}
+
+// parseModel parse a model read by reflection or type checking
+func parseModel(defs *definitions, m *Schema, t modelType) (*Schema, error) {
+	var err error
+
 	// if it was a pointer
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
 
+	// types controlling their own JSON
+	schema, exists, err := customSchema(defs, t)
+	if err != nil || exists {
+		return setCustomSchema(m, schema), err
+	}
+
 	switch {
-	case isIntType(t):
-		m["type"] = "integer"
-	case isFloatType(t):
-		m["type"] = "number"
+	case isIntType(t.Kind()):
+		m.Type = "integer"
+	case isFloatType(t.Kind()):
+		m.Type = "number"
 	case t.Kind() == reflect.Bool:
-		m["type"] = "boolean"
-	case isArrType(t):
-		m["type"] = "array"
-		m["items"] = parseDefinition(schemes, make(map[string]interface{}), t.Elem())
+		m.Type = "boolean"
+	case isArrType(t.Kind()):
+		m.Type = "array"
+		m.Items, err = parseModel(defs, &Schema{}, t.Elem())
 	case t.Kind() == reflect.String:
-		m["type"] = "string"
-	case t == reflect.TypeOf(time.Time{}):
-		m["type"] = "string"
-		m["format"] = "date-time"
+		m.Type = "string"
 	case t.Kind() == reflect.Map:
-		if t.Key().Kind() == reflect.Interface {
+		m.Type = "object"
+		m.AdditionalProperties = &AdditionalProperties{Allowed: true}
+
+		// keys encoding/json can't encode make a free-form object
+		switch key := t.Key(); {
+		case key.Kind() == reflect.String, key.TextMarshaler():
+		case isIntType(key.Kind()):
+			m.PropertyNames = intKeySchema(key.Kind() >= reflect.Uint)
+		default:
+			return m, nil
+		}
+
+		var elem modelType = t.Elem()
+		if elem.Kind() == reflect.Interface && defs.implementations[interfaceKey(elem.PkgPath(), elem.Name())] == nil {
 			break
 		}
-		m["type"] = "object"
-		m["additionalProperties"] = parseDefinition(schemes, map[string]interface{}{}, t.Elem())
+		m.AdditionalProperties.Schema, err = parseModel(defs, &Schema{}, elem)
 	case t.Kind() == reflect.Struct:
-		var req []string
-		props := make(map[string]interface{})
+		// anonymous structs are inlined, named types are registered and
+		// referenced by every use
+		var model *Schema = m
+		if t.Name() != "" {
+			name, err := defs.name(t.PkgPath(), t.Name())
+			if err != nil {
+				return m, err
+			}
 
-		// Stop recusive
-		if _, exists := schemes[t.Name()]; exists {
-			m["$ref"] = "#/components/schemes/" + t.Name()
-			break
+			// Stop recusive
+			m.Ref = schemaRef + name
+			if _, exists := defs.schemas[name]; exists {
+				break
+			}
+			model = &Schema{}
+			defs.schemas[name] = model
 		}
-		schemes[t.Name()] = "not recursive here!"
 
-		for i := 0; i < t.NumField(); i++ {
-			f := t.Field(i)
+		fields := make([]structField, t.NumField())
+		fieldTypes := make([]modelType, t.NumField())
+		for i := range fields {
+			fields[i], fieldTypes[i] = t.Field(i)
+			fields[i].anonymous = fields[i].anonymous && isStructType(fieldTypes[i])
+			fields[i].nullable = isNullableType(fieldTypes[i])
+		}
 
-			if unicode.IsLower(rune(f.Name[0])) {
-				continue
-			}
+		err = parseStruct(defs, model, fields, func(i int, s *Schema) (*Schema, error) {
+			return parseModel(defs, s, fieldTypes[i])
+		})
+	case t.Kind() == reflect.Interface:
+		return parseInterface(defs, m, t.PkgPath(), t.Name())
+	default:
+		m.Type = "object"
+	}
+	return m, err
+}
 
-			if f.Anonymous {
-				inner := parseDefinition(schemes, map[string]interface{}{}, f.Type)
-				raw, exists := inner["properties"]
-				if !exists {
-					continue
-				}
-				for k, v := range raw.(map[string]interface{}) {
-					props[k] = v
-				}
-				continue
-			}
+// structField a field of a struct parsed to a schema property
+type structField struct {
+	name string
+	tag  reflect.StructTag
+	// anonymous embedded structs, other embedded types are named fields
+	anonymous bool
+	// nullable pointers and sql.Null types encode null
+	nullable bool
+}
 
-			var name string = strings.ToLower(string(f.Name[0])) + f.Name[1:]
-			aa := make(map[string]interface{})
-			tagJSON := parseTag(f.Tag.Get("json"))
+// isStructType checks if type is a struct or a pointer to struct
+func isStructType(t modelType) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
 
-			nameTag, hasName := tagJSON["name"]
-			if nameTag == "-" {
-				// overide last tag
-				delete(props, name)
-				continue
-			}
+// isNullableType checks if the JSON of a type may be null
+func isNullableType(t modelType) bool {
+	return t.Kind() == reflect.Ptr || isSQLNull(t.PkgPath(), t.Name())
+}
 
-			if hasName {
-				name = nameTag
-			}
+// isSQLNull checks if a type is one of sql.NullString, sql.NullInt64...
+func isSQLNull(pkgPath, name string) bool {
+	return pkgPath == "database/sql" && strings.HasPrefix(name, "Null")
+}
 
-			docs := parseTag(f.Tag.Get("docs"))
-			if _, required := docs["required"]; required {
-				req = append(req, name)
+// parseStruct sets the properties of fields to m, parse returns the
+// schema of the field type at index i. Embedded structs are flattened or
+// composed with allOf, other options of defs infer fields like
+// encoding/json
+func parseStruct(defs *definitions, m *Schema, fields []structField, parse func(i int, s *Schema) (*Schema, error)) error {
+	var req, promotedReq []string
+	var allOf []*Schema
+	props := make(map[string]*Schema)
+	promoted := make(map[string]*Schema)
+
+	for i, f := range fields {
+		tagJSON := parseTag(f.tag.Get("json"))
+		nameTag, hasName := tagJSON["name"]
+
+		// fields of embedded structs without json name are promoted, even
+		// from unexported structs
+		if f.anonymous && !hasName {
+			inner, err := parse(i, &Schema{})
+			if err != nil {
+				return err
 			}
 
-			if description, exists := docs["description"]; exists {
-				aa["description"] = description
+			if ref := modelRef(defs, inner); defs.allOf && ref != "" {
+				allOf = append(allOf, &Schema{Ref: ref})
+				continue
 			}
 
-			if length, exists := docs["len"]; exists {
-				index := strings.IndexByte(length, '-')
-				if index == -1 {
-					aa["minLength"] = length
-					aa["maxLength"] = length
-				} else {
-					aa["minLength"] = length[:index]
-					aa["maxLength"] = length[index+1:]
+			base := resolveRef(defs, inner)
+			for k, v := range base.Properties {
+				if _, exists := promoted[k]; !exists {
+					promoted[k] = v
 				}
 			}
+			promotedReq = append(promotedReq, base.Required...)
+			continue
+		}
 
-			if enum, isEnum := docs["enum"]; isEnum {
-				aa["$ref"] = "#/components/schemes/" + enum + "Enum"
-				props[name] = aa
-				continue
-			}
+		// embedded structs with json name are fields, even unexported
+		if unicode.IsLower(rune(f.name[0])) && !(f.anonymous && hasName) {
+			continue
+		}
 
-			ff := parseDefinition(schemes, aa, f.Type)
+		var name string = strings.ToLower(string(f.name[0])) + f.name[1:]
+		aa := &Schema{}
 
-			if key, has := docs["key"]; has {
-				ff["additionalProperties"].(map[string]interface{})["description"] = key
-			}
-			props[name] = ff
+		if nameTag == "-" {
+			// overide last tag
+			delete(props, name)
+			continue
 		}
 
-		m["type"] = "object"
-		// Properties
-		if len(props) != 0 {
-			m["properties"] = props
+		if hasName {
+			name = nameTag
 		}
 
-		// Required fields
-		if len(req) != 0 {
-			m["required"] = req
+		docs := parseDocsTag(f.tag.Get("docs"))
+		_, required := docs["required"]
+		_, omitEmpty := tagJSON["omitempty"]
+		if required || (defs.infer && !f.nullable && !omitEmpty) {
+			req = append(req, name)
 		}
-		schemes[t.Name()] = m
-	default:
-		m["type"] = "object"
+
+		if description, exists := docs["description"]; exists {
+			aa.Description = description
+		}
+
+		var ff *Schema = aa
+		var err error
+		if enum, isEnum := docs["enum"]; isEnum {
+			// registered by SetDefinitions
+			aa.Ref = schemaRef + enum + "Enum"
+		} else if ff, err = parse(i, aa); err != nil {
+			return err
+		}
+
+		if ff, err = applyDocsTag(defs, ff, docs); err != nil {
+			return fmt.Errorf("field %s: %w", f.name, err)
+		}
+
+		if defs.infer {
+			ff = inferField(ff, f, tagJSON)
+		}
+		props[name] = ff
+	}
+
+	// fields of the struct hide promoted ones
+	for _, name := range promotedReq {
+		if _, hidden := props[name]; !hidden {
+			req = append(req, name)
+		}
+	}
+	for name, prop := range promoted {
+		if _, hidden := props[name]; !hidden {
+			props[name] = prop
+		}
+	}
+
+	own := m
+	if len(allOf) != 0 {
+		own = &Schema{}
+		m.AllOf = append(allOf, own)
+	}
+
+	own.Type = "object"
+	// Properties
+	if len(props) != 0 {
+		own.Properties = props
 	}
-	return m
+
+	// Required fields
+	if len(req) != 0 {
+		own.Required = req
+	}
+	return nil
 }
 
-func genRouteYAML(settings *DocSettings, r *chi.Mux) (doc string, err error) {
-	paths, err := walkRoute("", make(map[string]interface{}), make(map[string][]*ast.CommentGroup), r)
-	if err != nil {
-		return doc, err
+// modelRef returns the reference of a model schema, also when it was
+// registered by the parse returning it
+func modelRef(defs *definitions, s *Schema) string {
+	if s.Ref != "" {
+		return s.Ref
+	}
+
+	for name, model := range defs.schemas {
+		if model == s {
+			return schemaRef + name
+		}
+	}
+	return ""
+}
+
+// resolveRef returns the model referenced by s
+func resolveRef(defs *definitions, s *Schema) *Schema {
+	if model, exists := defs.schemas[strings.TrimPrefix(s.Ref, schemaRef)]; exists && s.Ref != "" {
+		return model
+	}
+	return s
+}
+
+// inferField sets nullable and string encoding of a property schema, the
+// schema is copied since it may be a registered model
+func inferField(s *Schema, f structField, tagJSON map[string]string) *Schema {
+	if _, asString := tagJSON["string"]; asString {
+		switch s.Type {
+		case "integer", "number", "boolean":
+			inferred := *s
+			inferred.Type = "string"
+			s = &inferred
+		}
+	}
+
+	if !f.nullable {
+		return s
+	}
+
+	// siblings of $ref are ignored
+	if s.Ref != "" {
+		return &Schema{Nullable: true, AllOf: []*Schema{s}}
+	}
+	inferred := *s
+	inferred.Nullable = true
+	return &inferred
+}
+
+// genRouteSpec generates the document of r, mainDir is the source
+// directory of package main, see mainDir
+func genRouteSpec(settings *DocSettings, r chi.Routes, mainDir string) (doc *Document, err error) {
+	// Parse definitions to schemas
+	defs := newDefinitions(settings.namer)
+	defs.infer = settings.infer
+	defs.allOf = settings.allOf
+	defs.implementations = settings.implementations
+	defs.mapped = settings.mapped
+	// enums are registered first, docs tags of models check their values
+	for _, d := range settings.definitions {
+		if s, ok := d.(StructEnum); ok {
+			defs.schemas[s.Name+"Enum"] = s.Schema()
+		}
 	}
 
-	// Parse definitions to YAML
-	schemes := make(map[string]interface{})
 	for _, d := range settings.definitions {
 		var t reflect.Type = reflect.TypeOf(d)
+		if _, ok := d.(StructEnum); ok {
+			continue
+		}
 
-		if s, ok := d.(StructEnum); ok {
-			schemes[s.Name+"Enum"] = s.Parse()
+		s, err := parseDefinition(defs, &Schema{}, t)
+		if err != nil {
+			return doc, err
+		}
+
+		// only structs register themselves, other named types like
+		// Schemer types are registered by name
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Name() == "" {
 			continue
 		}
-		parseDefinition(schemes, make(map[string]interface{}), t)
+		name, err := defs.name(t.PkgPath(), t.Name())
+		if err != nil {
+			return doc, err
+		}
+		if _, exists := defs.schemas[name]; !exists {
+			defs.schemas[name] = s
+		}
+	}
+
+	paths, err := walkRoute("", make(Paths), settings, newSources(mainDir), defs, r)
+	if err != nil {
+		return doc, err
+	}
+	assignOperationIDs(paths, settings.deduper)
+
+	// Models referenced by comments are looked up in packages
+	if len(settings.discover) != 0 {
+		pkgs, err := loadPackages(settings.discover, mainDir)
+		if err != nil {
+			return doc, err
+		}
+
+		if err = discoverDefinitions(defs, pkgs, paths); err != nil {
+			return doc, err
+		}
+	}
+
+	// Parse authorization to security schemes
+	auths := make(map[string]*SecurityScheme)
+	for _, a := range settings.auths {
+		if err = a.DecodeScheme(auths); err != nil {
+			return doc, err
+		}
+	}
+
+	doc = &Document{
+		OpenAPI: "3.0.0",
+		Info: Info{
+			Title:       settings.Title,
+			Description: settings.Description,
+			Version:     settings.Version,
+		},
+		Paths: paths,
+		Components: &Components{
+			Schemas:         defs.schemas,
+			SecuritySchemes: auths,
+		},
+		Tags: []*Tag{
+			{
+				Name: "API",
+			},
+		},
 	}
-	settings.Set("components.schemes", schemes)
 
 	// Set base path
 	if settings.BasePath != "" {
-		settings.Set("servers", []Server{
+		doc.Servers = []Server{
 			{
 				URL: settings.BasePath,
 			},
-		})
+		}
 	}
 
-	// Parse authorization to YAML
-	auths := make(map[string]interface{})
-	for _, a := range settings.auths {
-		if err = a.Decode(auths); err != nil {
-			return doc, err
-		}
+	// Values set by path are applied over the generated document
+	if err = settings.apply(doc); err != nil {
+		return doc, err
 	}
-	settings.Set("components.securitySchemes", auths)
-	settings.Set("paths", paths)
-	settings.Set("tags", [](interface{}){
-		map[string]interface{}{
-			"name": "API",
-		},
-	})
-	raw := make(map[string]interface{})
-	if err = settings.Decode(raw); err != nil {
+
+	if settings.strict {
+		err = Validate(doc)
+	}
+	return doc, err
+}
+
+func genRouteYAML(settings *DocSettings, r chi.Routes, mainDir string) (doc string, err error) {
+	spec, err := genRouteSpec(settings, r, mainDir)
+	if err != nil {
 		return doc, err
 	}
 
-	buffer, err := yaml.Marshal(raw)
-	return string(buffer), err
+	var buffer bytes.Buffer
+	err = spec.WriteSpec(&buffer, FormatYAML)
+	return buffer.String(), err
 }
 
 func readImage(handle HandlerImage, logo io.Writer) error {
@@ -542,54 +1100,15 @@ func joinPath(p0, p1 string) string {
 	return p0 + "/" + p1
 }
 
-// AddRouteDoc adds documention to route
+// AddRouteDoc adds documention to route, the spec is generated at once,
+// see NewRouteDoc to generate it on the first request
 func AddRouteDoc(root *chi.Mux, docpath string, settings *DocSettings, paths ...string) error {
-	var urlDoc string = docpath
-
-	for _, path := range paths {
-		urlDoc = joinPath(urlDoc, path)
-	}
-
-	var html string = replaceHTML(htmls[settings.Render], settings.Title, urlDoc, settings)
-
-	// set logo swagger
-	settings.Set("info.x-logo.url", joinPath(urlDoc, "logo.png"))
+	d := newRouteDoc(root, docpath, settings, paths...)
 
-	docs, err := genRouteYAML(settings, root)
-	if err != nil {
+	// routes are only added once the spec is generated
+	if err := d.Refresh(); err != nil {
 		return err
 	}
-
-	// Create page index
-	root.Get(docpath, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(html))
-	})
-
-	// Read static logo
-	var logo bytes.Buffer
-	err = readImage(settings.handlerLogo, &logo)
-	if err == nil {
-		// Set logo
-		//Adds logo router
-		root.Get(joinPath(urlDoc, "logo.png"), func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Add("Content-Type", "image/png")
-			w.Write(logo.Bytes())
-		})
-	}
-
-	// Read static icon
-	var icon bytes.Buffer
-	if err := readImage(settings.handlerIcon, &icon); err != nil {
-		root.Get(joinPath(urlDoc, "favicon.png"), func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Add("Content-Type", "image/png")
-			w.Write(icon.Bytes())
-		})
-	}
-
-	// Create route for docs generation
-	root.Get(joinPath(urlDoc, "docs.yaml"), func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Add("Content-Type", "text/x-yaml")
-		w.Write([]byte(docs))
-	})
+	d.register()
 	return nil
 }
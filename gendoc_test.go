@@ -0,0 +1,167 @@
+package chidoc
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp/syntax"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestParseRoutePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		params  []*Parameter
+	}{
+		{"/users", "/users", []*Parameter{}},
+		{"/users/{id}", "/users/{id}", []*Parameter{
+			{In: "path", Name: "id", Required: true, Schema: &Schema{Type: "string"}},
+		}},
+		{"/users/{id:[0-9]+}", "/users/{id}", []*Parameter{
+			{In: "path", Name: "id", Required: true, Schema: &Schema{Type: "integer"}},
+		}},
+		{"/lang/{code:[a-z]{2}}/page", "/lang/{code}/page", []*Parameter{
+			{In: "path", Name: "code", Required: true, Schema: &Schema{Type: "string", Pattern: "^(?:[a-z]{2})$"}},
+		}},
+		{"/range/{from}-{to}", "/range/{from}-{to}", []*Parameter{
+			{In: "path", Name: "from", Required: true, Schema: &Schema{Type: "string"}},
+			{In: "path", Name: "to", Required: true, Schema: &Schema{Type: "string"}},
+		}},
+		{"/items/{id:[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}}", "/items/{id}", []*Parameter{
+			{In: "path", Name: "id", Required: true, Schema: &Schema{Type: "string", Format: "uuid"}},
+		}},
+		{"/items/{id:^[0-9a-fA-F]{8}-([0-9a-fA-F]{4}-){3}[0-9a-fA-F]{12}$}", "/items/{id}", []*Parameter{
+			{In: "path", Name: "id", Required: true, Schema: &Schema{Type: "string", Format: "uuid"}},
+		}},
+		{"/items/{id:[0-9a-f-]{36}}", "/items/{id}", []*Parameter{
+			{In: "path", Name: "id", Required: true, Schema: &Schema{Type: "string", Pattern: "^(?:[0-9a-f-]{36})$"}},
+		}},
+		{"/posts/{slug:[a-z0-9]+-[a-z0-9-]+}", "/posts/{slug}", []*Parameter{
+			{In: "path", Name: "slug", Required: true, Schema: &Schema{Type: "string", Pattern: "^(?:[a-z0-9]+-[a-z0-9-]+)$"}},
+		}},
+		{"/broken/{id", "/broken/{id", []*Parameter{}},
+	}
+
+	for _, tt := range tests {
+		path, params := parseRoutePattern(tt.pattern)
+		if path != tt.path {
+			t.Errorf("parseRoutePattern(%q) path = %q, want %q", tt.pattern, path, tt.path)
+		}
+		if !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("parseRoutePattern(%q) params = %+v, want %+v", tt.pattern, params, tt.params)
+		}
+	}
+}
+
+func TestIsDigitsRegex(t *testing.T) {
+	tests := []struct {
+		regex string
+		want  bool
+	}{
+		{"[0-9]+", true},
+		{`\d+`, true},
+		{"^[0-9]{1,5}$", true},
+		{"[0-9]*", true},
+		{"(1|2)[0-9]", true},
+		{"7", true},
+		{"[0-9a-f]+", false},
+		{"[a-z]+", false},
+		{"-?[0-9]+", false},
+		{".*", false},
+		{"^$", false},
+	}
+
+	for _, tt := range tests {
+		re, err := syntax.Parse(tt.regex, syntax.Perl)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.regex, err)
+		}
+		if got := isDigitsRegex(re.Simplify()); got != tt.want {
+			t.Errorf("isDigitsRegex(%q) = %v, want %v", tt.regex, got, tt.want)
+		}
+	}
+}
+
+type testTimestamps struct {
+	Created string `json:"created"`
+}
+
+type testAccount struct {
+	ID string `json:"id" docs:"required"`
+}
+
+type testAdmin struct {
+	testAccount
+	testTimestamps `json:"timestamps"`
+	Roles          []string `json:"roles"`
+}
+
+func TestEmbeddedStructs(t *testing.T) {
+	tests := []struct {
+		allOf bool
+		want  string
+	}{
+		{false, `{"type":"object","required":["id"],"properties":{"id":{"type":"string"},"roles":{"type":"array","items":{"type":"string"}},"timestamps":{"$ref":"#/components/schemas/testTimestamps"}}}`},
+		{true, `{"allOf":[{"$ref":"#/components/schemas/testAccount"},{"type":"object","properties":{"roles":{"type":"array","items":{"type":"string"}},"timestamps":{"$ref":"#/components/schemas/testTimestamps"}}}]}`},
+	}
+
+	for _, tt := range tests {
+		defs := newDefinitions(nil)
+		defs.allOf = tt.allOf
+		if _, err := schemaOf(defs, reflect.TypeOf(testAdmin{})); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := json.Marshal(defs.schemas["testAdmin"])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("allOf %v: testAdmin = %s, want %s", tt.allOf, got, tt.want)
+		}
+		if defs.schemas["testTimestamps"] == nil {
+			t.Errorf("allOf %v: embedded field with json name is not a model", tt.allOf)
+		}
+	}
+}
+
+func TestSetMount(t *testing.T) {
+	files := http.FileServer(http.Dir("."))
+
+	r := chi.NewRouter()
+	r.Mount("/static", files)
+	r.Mount("/proxy/", files)
+	r.Mount("/other", files)
+
+	settings := NewDocSettings("test", RedocRender)
+	settings.SetAnyMethods("GET", "POST")
+	settings.SetMount("/static", "get", NewOp().Summary("static files"))
+	settings.SetMount("/proxy", "*", NewOp().Summary("proxied"))
+
+	doc, err := GenerateSpec(r, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for path, item := range doc.Paths {
+		for method, op := range item.Operations() {
+			got[method+" "+path] = op.Summary
+		}
+	}
+
+	// mounts without documentation have the default operation
+	want := map[string]string{
+		"get /static/{wildcard}": "static files",
+		"get /proxy/{wildcard}":  "proxied",
+		"post /proxy/{wildcard}": "proxied",
+		"get /other/{wildcard}":  "",
+		"post /other/{wildcard}": "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("operations = %v, want %v", got, want)
+	}
+}
@@ -1,10 +1,16 @@
 module github.com/n0bode/chidoc
 
-go 1.15
+go 1.26.0
 
 require (
 	github.com/ghodss/yaml v1.0.0
-	github.com/go-chi/chi/v5 v5.0.7 // indirect
-	golang.org/x/image v0.0.0-20210607152325-775e3b0c77b9 // indirect
+	github.com/go-chi/chi/v5 v5.0.7
+	golang.org/x/image v0.0.0-20210607152325-775e3b0c77b9
+	golang.org/x/tools v0.50.0
+)
+
+require (
+	golang.org/x/mod v0.41.0 // indirect
+	golang.org/x/sync v0.23.0 // indirect
 	gopkg.in/yaml.v2 v2.3.0 // indirect
 )
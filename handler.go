@@ -0,0 +1,131 @@
+package chidoc
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// closureName matches the name the runtime gives to func literals, func
+// literals nested in others are only numbered
+var closureName = regexp.MustCompile(`^(func)?\d+$`)
+
+// funcName parts of a function name reported by the runtime
+type funcName struct {
+	pkgPath string
+	// recv type of the method receiver, empty for functions
+	recv string
+	name string
+	// closure function literals are named by the function declaring them
+	closure bool
+}
+
+// Unwrapper is implemented by middleware handlers to expose the handler
+// they wrap, so the documentation resolves to the endpoint
+type Unwrapper interface {
+	Unwrap() http.Handler
+}
+
+// unwrapHandler returns the endpoint behind chained middlewares
+func unwrapHandler(handler http.Handler) http.Handler {
+	for handler != nil {
+		var next http.Handler
+		switch h := handler.(type) {
+		case *chi.ChainHandler:
+			next = h.Endpoint
+		case Unwrapper:
+			next = h.Unwrap()
+		}
+
+		if next == nil {
+			break
+		}
+		handler = next
+	}
+	return handler
+}
+
+// String returns the qualified name, like github.com/acme/api.Service.GetUser
+func (fn funcName) String() string {
+	if fn.recv != "" {
+		return fn.pkgPath + "." + fn.recv + "." + fn.name
+	}
+	return fn.pkgPath + "." + fn.name
+}
+
+// parseFuncName splits a runtime function name like
+// github.com/acme/api.(*Service).GetUser-fm, closures resolve to the
+// function declaring them
+func parseFuncName(full string) (fn funcName) {
+	// dots in the last element of the import path are escaped as %2e
+	var start int = strings.LastIndex(full, "/") + 1
+	var dot int = strings.Index(full[start:], ".")
+	if dot < 0 {
+		fn.name = full
+		return fn
+	}
+
+	fn.pkgPath = strings.ReplaceAll(full[:start+dot], "%2e", ".")
+
+	// instances of generic functions and types, like List[...]
+	var name string = strings.ReplaceAll(full[start+dot+1:], "[...]", "")
+	parts := strings.Split(strings.TrimSuffix(name, "-fm"), ".")
+	for len(parts) > 1 && closureName.MatchString(parts[len(parts)-1]) {
+		parts = parts[:len(parts)-1]
+		fn.closure = true
+	}
+
+	fn.name = parts[len(parts)-1]
+	if len(parts) > 1 {
+		fn.recv = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(parts[0], "("), "*"), ")")
+	}
+	return fn
+}
+
+// mountName the function of the handler chi mounts other handlers with
+var mountName string = runtime.FuncForPC(reflect.ValueOf((*chi.Mux).Mount).Pointer()).Name()
+
+// isMountHandler reports whether handler was added by Mux.Mount, it hides
+// the mounted handler
+func isMountHandler(handler http.Handler) bool {
+	return strings.HasPrefix(handlerFunc(handler).Name(), mountName+".func")
+}
+
+// sameHandler reports whether a and b are the same handler, chi stores
+// the handler of any method routes under every method
+func sameHandler(a, b http.Handler) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case va.Type() != vb.Type():
+		return false
+	case va.Kind() == reflect.Func:
+		return va.Pointer() == vb.Pointer()
+	case va.Type().Comparable():
+		return a == b
+	}
+	return false
+}
+
+// handlerFunc returns the function serving handler, for types
+// implementing http.Handler it's their ServeHTTP method
+func handlerFunc(handler http.Handler) *runtime.Func {
+	v := reflect.ValueOf(handler)
+	if v.Kind() == reflect.Func {
+		return runtime.FuncForPC(v.Pointer())
+	}
+
+	// methods of a value receiver are wrapped for pointers
+	var t reflect.Type = v.Type()
+	if t.Kind() == reflect.Ptr {
+		if method, exists := t.Elem().MethodByName("ServeHTTP"); exists {
+			return runtime.FuncForPC(method.Func.Pointer())
+		}
+	}
+
+	method, _ := t.MethodByName("ServeHTTP")
+	return runtime.FuncForPC(method.Func.Pointer())
+}
@@ -0,0 +1,70 @@
+package chidoc
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestParseFuncName(t *testing.T) {
+	tests := []struct {
+		full string
+		want funcName
+	}{
+		{"main.GetUser", funcName{pkgPath: "main", name: "GetUser"}},
+		{"main.main.func1", funcName{pkgPath: "main", name: "main", closure: true}},
+		{"main.main.func1.2", funcName{pkgPath: "main", name: "main", closure: true}},
+		{"main.(*API).List-fm", funcName{pkgPath: "main", recv: "API", name: "List"}},
+		{"main.API.Get-fm", funcName{pkgPath: "main", recv: "API", name: "Get"}},
+		{"main.(*API).List.func1", funcName{pkgPath: "main", recv: "API", name: "List", closure: true}},
+		{"github.com/acme/api.Handler", funcName{pkgPath: "github.com/acme/api", name: "Handler"}},
+		{"github.com/acme/api%2ev2.Handler", funcName{pkgPath: "github.com/acme/api.v2", name: "Handler"}},
+		{"github.com/acme/api.List[...]", funcName{pkgPath: "github.com/acme/api", name: "List"}},
+		{"github.com/acme/api.(*Store[...]).Get-fm", funcName{pkgPath: "github.com/acme/api", recv: "Store", name: "Get"}},
+		{"net/http.NotFound", funcName{pkgPath: "net/http", name: "NotFound"}},
+		{"noPackage", funcName{name: "noPackage"}},
+	}
+
+	for _, tt := range tests {
+		if got := parseFuncName(tt.full); got != tt.want {
+			t.Errorf("parseFuncName(%q) = %+v, want %+v", tt.full, got, tt.want)
+		}
+	}
+}
+
+// testLogged middleware exposing the handler it wraps
+type testLogged struct {
+	next http.Handler
+}
+
+func (h testLogged) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.next.ServeHTTP(w, r)
+}
+
+func (h testLogged) Unwrap() http.Handler {
+	return h.next
+}
+
+func TestUnwrapHandler(t *testing.T) {
+	logged := func(next http.Handler) http.Handler {
+		return testLogged{next}
+	}
+
+	r := chi.NewRouter()
+	r.With(logged, logged).Get("/chained", testGetPost)
+	r.Handle("/wrapped", testLogged{testLogged{http.HandlerFunc(testGetPost)}})
+	r.Handle("/plain", testLogged{})
+
+	want := map[string]string{
+		"/chained": "testGetPost",
+		"/wrapped": "testGetPost",
+		"/plain":   "testLogged",
+	}
+	for _, route := range r.Routes() {
+		handler := unwrapHandler(route.Handlers["GET"])
+		if got := parseFuncName(handlerFunc(handler).Name()); got.name != want[route.Pattern] && got.recv != want[route.Pattern] {
+			t.Errorf("%s resolves to %s, want %s", route.Pattern, got, want[route.Pattern])
+		}
+	}
+}
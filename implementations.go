@@ -0,0 +1,103 @@
+package chidoc
+
+import (
+	"reflect"
+)
+
+// implementation types documented for an interface
+type implementation struct {
+	types         []reflect.Type
+	discriminator string
+}
+
+// interfaceKey identifies an interface by qualified name, interface{}
+// included
+func interfaceKey(pkgPath, name string) string {
+	if name == "" {
+		return "interface{}"
+	}
+	return qualifiedName(pkgPath, name)
+}
+
+// RegisterImplementations documents fields of the interface type iface as
+// one of the models of impls, iface is a pointer to the interface
+//
+//	docSettings.RegisterImplementations((*Shape)(nil), Circle{}, Square{})
+func (s *DocSettings) RegisterImplementations(iface interface{}, impls ...interface{}) {
+	var t reflect.Type = reflect.TypeOf(iface).Elem()
+	var key string = interfaceKey(t.PkgPath(), t.Name())
+
+	impl, exists := s.implementations[key]
+	if !exists {
+		impl = &implementation{}
+		s.implementations[key] = impl
+	}
+
+	for _, v := range impls {
+		impl.types = append(impl.types, reflect.TypeOf(v))
+	}
+}
+
+// SetDiscriminator sets the property telling the implementations of
+// iface apart, its values are the schema names of the implementations
+func (s *DocSettings) SetDiscriminator(iface interface{}, property string) {
+	var t reflect.Type = reflect.TypeOf(iface).Elem()
+	var key string = interfaceKey(t.PkgPath(), t.Name())
+
+	impl, exists := s.implementations[key]
+	if !exists {
+		impl = &implementation{}
+		s.implementations[key] = impl
+	}
+	impl.discriminator = property
+}
+
+// parseInterface parse an interface as one of its implementations, named
+// interfaces are registered as models. Without implementations it's an
+// object
+func parseInterface(defs *definitions, m *Schema, pkgPath, typeName string) (*Schema, error) {
+	impl, exists := defs.implementations[interfaceKey(pkgPath, typeName)]
+	if !exists || len(impl.types) == 0 {
+		m.Type = "object"
+		return m, nil
+	}
+
+	var model *Schema = m
+	if typeName != "" {
+		name, err := defs.name(pkgPath, typeName)
+		if err != nil {
+			return m, err
+		}
+
+		// Stop recusive
+		m.Ref = schemaRef + name
+		if _, exists := defs.schemas[name]; exists {
+			return m, nil
+		}
+		model = &Schema{}
+		defs.schemas[name] = model
+	}
+
+	mapping := make(map[string]string)
+	for _, t := range impl.types {
+		s, err := parseDefinition(defs, &Schema{}, t)
+		if err != nil {
+			return m, err
+		}
+
+		// implementations are referenced to be told apart
+		if ref := modelRef(defs, s); ref != "" {
+			mapping[ref[len(schemaRef):]] = ref
+			s = &Schema{Ref: ref}
+		}
+		model.OneOf = append(model.OneOf, s)
+	}
+
+	if impl.discriminator != "" {
+		model.Discriminator = &Discriminator{
+			PropertyName: impl.discriminator,
+			Mapping:      mapping,
+		}
+	}
+	return m, nil
+}
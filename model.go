@@ -0,0 +1,177 @@
+package chidoc
+
+import (
+	"go/types"
+	"reflect"
+)
+
+// modelType a Go type documented as a model, read by reflection or from
+// type checked sources for discovered definitions. Both are parsed by
+// parseModel so they follow the same rules
+type modelType interface {
+	Kind() reflect.Kind
+	PkgPath() string
+	Name() string
+	String() string
+	// Elem element of pointers, arrays, slices and maps
+	Elem() modelType
+	// Key key of maps
+	Key() modelType
+	NumField() int
+	Field(i int) (f structField, t modelType)
+	// TextMarshaler checks if the type implements encoding.TextMarshaler
+	TextMarshaler() bool
+	// Schemer checks if the type or its pointer implements Schemer,
+	// schema is nil when it can't be called
+	Schemer() (schema *Schema, implements bool)
+}
+
+// reflectModel a model read by reflection
+type reflectModel struct {
+	t reflect.Type
+}
+
+func (m reflectModel) Kind() reflect.Kind { return m.t.Kind() }
+func (m reflectModel) PkgPath() string    { return m.t.PkgPath() }
+func (m reflectModel) Name() string       { return m.t.Name() }
+func (m reflectModel) String() string     { return m.t.String() }
+func (m reflectModel) Elem() modelType    { return reflectModel{m.t.Elem()} }
+func (m reflectModel) Key() modelType     { return reflectModel{m.t.Key()} }
+func (m reflectModel) NumField() int      { return m.t.NumField() }
+
+func (m reflectModel) Field(i int) (structField, modelType) {
+	f := m.t.Field(i)
+	return structField{name: f.Name, tag: f.Tag, anonymous: f.Anonymous}, reflectModel{f.Type}
+}
+
+func (m reflectModel) TextMarshaler() bool {
+	return m.t.Implements(textMarshalerType)
+}
+
+func (m reflectModel) Schemer() (*Schema, bool) {
+	var schema Schema
+	switch {
+	case m.t.Implements(schemerType):
+		schema = reflect.Zero(m.t).Interface().(Schemer).OpenAPISchema()
+	case reflect.PointerTo(m.t).Implements(schemerType):
+		schema = reflect.New(m.t).Interface().(Schemer).OpenAPISchema()
+	default:
+		return nil, false
+	}
+	return &schema, true
+}
+
+// checkedModel a model read from type checked sources
+type checkedModel struct {
+	t types.Type
+}
+
+func newCheckedModel(t types.Type) checkedModel {
+	return checkedModel{types.Unalias(t)}
+}
+
+func (m checkedModel) Kind() reflect.Kind {
+	switch u := m.t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Kind() >= types.Bool && u.Kind() <= types.Complex128:
+			// both packages list these kinds in the same order
+			return reflect.Kind(u.Kind())
+		case u.Kind() == types.String:
+			return reflect.String
+		case u.Kind() == types.UnsafePointer:
+			return reflect.UnsafePointer
+		}
+	case *types.Pointer:
+		return reflect.Ptr
+	case *types.Array:
+		return reflect.Array
+	case *types.Slice:
+		return reflect.Slice
+	case *types.Map:
+		return reflect.Map
+	case *types.Chan:
+		return reflect.Chan
+	case *types.Signature:
+		return reflect.Func
+	case *types.Struct:
+		return reflect.Struct
+	case *types.Interface:
+		return reflect.Interface
+	}
+	return reflect.Invalid
+}
+
+// obj type name of named types
+func (m checkedModel) obj() *types.TypeName {
+	if named, isNamed := m.t.(*types.Named); isNamed {
+		return named.Obj()
+	}
+	return nil
+}
+
+func (m checkedModel) PkgPath() string {
+	if obj := m.obj(); obj != nil && obj.Pkg() != nil {
+		return obj.Pkg().Path()
+	}
+	return ""
+}
+
+func (m checkedModel) Name() string {
+	if obj := m.obj(); obj != nil {
+		return obj.Name()
+	}
+	return ""
+}
+
+func (m checkedModel) String() string {
+	return types.TypeString(m.t, (*types.Package).Name)
+}
+
+func (m checkedModel) Elem() modelType {
+	switch u := m.t.Underlying().(type) {
+	case *types.Pointer:
+		return newCheckedModel(u.Elem())
+	case *types.Array:
+		return newCheckedModel(u.Elem())
+	case *types.Slice:
+		return newCheckedModel(u.Elem())
+	case *types.Map:
+		return newCheckedModel(u.Elem())
+	}
+	panic("chidoc: Elem of " + m.String())
+}
+
+func (m checkedModel) Key() modelType {
+	return newCheckedModel(m.t.Underlying().(*types.Map).Key())
+}
+
+func (m checkedModel) NumField() int {
+	return m.t.Underlying().(*types.Struct).NumFields()
+}
+
+func (m checkedModel) Field(i int) (structField, modelType) {
+	u := m.t.Underlying().(*types.Struct)
+	f := u.Field(i)
+	return structField{name: f.Name(), tag: reflect.StructTag(u.Tag(i)), anonymous: f.Anonymous()}, newCheckedModel(f.Type())
+}
+
+func (m checkedModel) TextMarshaler() bool {
+	return types.NewMethodSet(m.t).Lookup(nil, "MarshalText") != nil
+}
+
+func (m checkedModel) Schemer() (*Schema, bool) {
+	sel := types.NewMethodSet(types.NewPointer(m.t)).Lookup(nil, "OpenAPISchema")
+	if sel == nil {
+		return nil, false
+	}
+
+	sig := sel.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return nil, false
+	}
+	result := newCheckedModel(sig.Results().At(0).Type())
+	return nil, result.PkgPath() == schemaType.PkgPath() && result.Name() == schemaType.Name()
+}
+
+var schemaType reflect.Type = reflect.TypeOf(Schema{})
@@ -0,0 +1,255 @@
+package chidoc
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// registry operations documented by code, by handler
+var registry = struct {
+	sync.RWMutex
+	operations map[uintptr]*OperationBuilder
+}{
+	operations: make(map[uintptr]*OperationBuilder),
+}
+
+// OperationBuilder documents a handler from Go code, the operation is
+// preferred over the YAML in the handler comment. Configure it before
+// the spec is generated
+type OperationBuilder struct {
+	op           Operation
+	doc          *runtime.Func
+	params       []builderParam
+	paramStructs []reflect.Type
+	body         reflect.Type
+	responses    map[int]reflect.Type
+}
+
+// builderParam a parameter with the Go type of its schema
+type builderParam struct {
+	param *Parameter
+	t     reflect.Type
+}
+
+// Op starts the documentation of handler
+//
+//	chidoc.Op(GetUser).Summary("gets a user").Response(200, User{})
+func Op(handler http.HandlerFunc) *OperationBuilder {
+	return OpHandler(handler)
+}
+
+// NewOp starts the documentation of an operation without handler, see
+// DocSettings.SetMount
+func NewOp() *OperationBuilder {
+	return &OperationBuilder{
+		responses: make(map[int]reflect.Type),
+	}
+}
+
+// OpHandler starts the documentation of a http.Handler, or continues
+// the one already started
+func OpHandler(handler http.Handler) *OperationBuilder {
+	if h, isJSON := handler.(*JSONHandler); isJSON {
+		return h.op
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	var key uintptr = handlerFunc(handler).Entry()
+	if b, exists := registry.operations[key]; exists {
+		return b
+	}
+
+	b := NewOp()
+	registry.operations[key] = b
+	return b
+}
+
+// lookupOperation returns the builder registered for handler
+func lookupOperation(handler http.Handler) (b *OperationBuilder, exists bool) {
+	if h, isJSON := handler.(*JSONHandler); isJSON {
+		return h.op, true
+	}
+
+	registry.RLock()
+	b, exists = registry.operations[handlerFunc(handler).Entry()]
+	registry.RUnlock()
+	return b, exists
+}
+
+// Summary sets the operation summary
+func (b *OperationBuilder) Summary(summary string) *OperationBuilder {
+	b.op.Summary = summary
+	return b
+}
+
+// Description sets the operation description
+func (b *OperationBuilder) Description(description string) *OperationBuilder {
+	b.op.Description = description
+	return b
+}
+
+// Tags adds tags to the operation
+func (b *OperationBuilder) Tags(tags ...string) *OperationBuilder {
+	b.op.Tags = append(b.op.Tags, tags...)
+	return b
+}
+
+// OperationID sets the operation id
+func (b *OperationBuilder) OperationID(id string) *OperationBuilder {
+	b.op.OperationID = id
+	return b
+}
+
+// Deprecated marks the operation as deprecated
+func (b *OperationBuilder) Deprecated() *OperationBuilder {
+	b.op.Deprecated = true
+	return b
+}
+
+// Param adds a parameter in path, query, header or cookie, its schema
+// is the type of v
+func (b *OperationBuilder) Param(in, name, description string, required bool, v interface{}) *OperationBuilder {
+	b.params = append(b.params, builderParam{
+		param: &Parameter{
+			In:          in,
+			Name:        name,
+			Description: description,
+			Required:    required || in == "path",
+		},
+		t: reflect.TypeOf(v),
+	})
+	return b
+}
+
+// Body sets the JSON request body to the type of v
+func (b *OperationBuilder) Body(v interface{}) *OperationBuilder {
+	b.body = reflect.TypeOf(v)
+	return b
+}
+
+// Response adds a response for status, its JSON content is the type of
+// v, nil for a response without content
+func (b *OperationBuilder) Response(status int, v interface{}) *OperationBuilder {
+	b.responses[status] = reflect.TypeOf(v)
+	return b
+}
+
+// Security requires the security scheme name with scopes
+func (b *OperationBuilder) Security(name string, scopes ...string) *OperationBuilder {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	b.op.Security = append(b.op.Security, SecurityRequirement{name: scopes})
+	return b
+}
+
+// build creates the operation, Go types are registered as models. The
+// operation in the comment of the doc function is completed by the builder
+func (b *OperationBuilder) build(defs *definitions, src *sources) (*Operation, error) {
+	op := b.op
+	op.Tags = append([]string(nil), b.op.Tags...)
+	op.Security = append([]SecurityRequirement(nil), b.op.Security...)
+	if b.doc != nil {
+		base, err := funcDescription(b.doc, src)
+		if err != nil {
+			return nil, err
+		}
+		op = mergeOperation(*base, op)
+	}
+
+	if len(op.Tags) == 0 {
+		op.Tags = []string{"API"}
+	}
+
+	for _, p := range b.params {
+		param := *p.param
+		if p.t != nil {
+			schema, err := schemaOf(defs, p.t)
+			if err != nil {
+				return nil, err
+			}
+			param.Schema = schema
+		}
+		op.Parameters = append(op.Parameters, &param)
+	}
+
+	for _, t := range b.paramStructs {
+		params, err := parseParams(defs, t)
+		if err != nil {
+			return nil, err
+		}
+		op.Parameters = append(op.Parameters, params...)
+	}
+
+	if b.body != nil {
+		schema, err := schemaOf(defs, b.body)
+		if err != nil {
+			return nil, err
+		}
+		if op.RequestBody == nil {
+			op.RequestBody = &RequestBody{Required: true}
+		}
+		op.RequestBody.Content = jsonContent(schema)
+	}
+
+	for status, t := range b.responses {
+		if op.Responses == nil {
+			op.Responses = make(Responses)
+		}
+
+		resp, exists := op.Responses[strconv.Itoa(status)]
+		if !exists {
+			resp = &Response{Description: http.StatusText(status)}
+		}
+		if t != nil {
+			schema, err := schemaOf(defs, t)
+			if err != nil {
+				return nil, err
+			}
+			resp.Content = jsonContent(schema)
+		}
+		op.Responses[strconv.Itoa(status)] = resp
+	}
+	return &op, nil
+}
+
+// mergeOperation overrides the fields of base set in op
+func mergeOperation(base, op Operation) Operation {
+	if op.Summary != "" {
+		base.Summary = op.Summary
+	}
+	if op.Description != "" {
+		base.Description = op.Description
+	}
+	if op.OperationID != "" {
+		base.OperationID = op.OperationID
+	}
+	if len(op.Tags) != 0 {
+		base.Tags = op.Tags
+	}
+	if len(op.Security) != 0 {
+		base.Security = op.Security
+	}
+	base.Deprecated = base.Deprecated || op.Deprecated
+	return base
+}
+
+// schemaOf returns the schema of t, named models are registered and
+// referenced
+func schemaOf(defs *definitions, t reflect.Type) (*Schema, error) {
+	return parseDefinition(defs, &Schema{}, t)
+}
+
+// jsonContent content of a JSON media type
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{
+		mimeJSON: {
+			Schema: schema,
+		},
+	}
+}
@@ -0,0 +1,143 @@
+package chidoc
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// OperationIDDeduper renames the operationId of the operation at method
+// and path, n counts the attempts from 1 until the name is not used
+type OperationIDDeduper func(id, method, path string, n int) string
+
+var (
+	// SuffixDeduper appends a counter, like getUser2
+	SuffixDeduper OperationIDDeduper = func(id, method, path string, n int) string {
+		return id + strconv.Itoa(n+1)
+	}
+
+	// MethodPathDeduper names by method and path, like getUsersId
+	MethodPathDeduper OperationIDDeduper = func(id, method, path string, n int) string {
+		return methodPathID(method, path)
+	}
+)
+
+// methods order operations are named in, so duplicates are renamed the
+// same way on every generation
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// operationName returns the operationId of the function documenting
+// handler, ServeHTTP methods by their type. Function literals are named
+// by method and path, unless the declaration containing them documents
+// them like handler factories
+func operationName(handler http.Handler, src *sources) string {
+	funcPC := handlerFunc(handler)
+	if builder, exists := lookupOperation(handler); exists && builder.doc != nil {
+		funcPC = builder.doc
+	}
+
+	fn := parseFuncName(funcPC.Name())
+	if fn.closure && !hasDoc(funcPC, src) {
+		return ""
+	}
+
+	var name string = fn.name
+	if name == "ServeHTTP" {
+		name = fn.recv
+	}
+	return lowerCamel(name)
+}
+
+// methodPathID names an operation by method and path, like getUsersId
+func methodPathID(method, path string) string {
+	var id strings.Builder
+	id.WriteString(strings.ToLower(method))
+
+	words := strings.FieldsFunc(path, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		id.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return id.String()
+}
+
+// lowerCamel lowers the leading upper case letters, like HTTPGet to httpGet
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+
+		// the last upper case letter before a lower case one starts a word
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// assignOperationIDs names operations without operationId by method and
+// path and renames generated operationIds used more than once, the ones
+// set by comments or code are kept
+func assignOperationIDs(paths Paths, deduper OperationIDDeduper) {
+	if deduper == nil {
+		deduper = SuffixDeduper
+	}
+
+	keys := make([]string, 0, len(paths))
+	for path := range paths {
+		keys = append(keys, path)
+	}
+	sort.Strings(keys)
+
+	used := make(map[string]int)
+	var generated []func()
+	for _, path := range keys {
+		// operations of any method are named like one more method
+		ops := paths[path].Operations()
+		if op, isOp := paths[path].Extensions["x-any-method"].(*Operation); isOp {
+			ops["any"] = op
+		}
+
+		for _, method := range append(methods, "any") {
+			op, exists := ops[method]
+			if !exists {
+				continue
+			}
+
+			if !op.generatedID && op.OperationID != "" {
+				used[op.OperationID]++
+				continue
+			}
+
+			if op.OperationID == "" {
+				op.OperationID = methodPathID(method, path)
+			}
+
+			path, method := path, method
+			generated = append(generated, func() {
+				var id string = op.OperationID
+				for n := 1; used[id] > 0; n++ {
+					if next := deduper(op.OperationID, method, path, n); used[next] == 0 {
+						id = next
+						break
+					}
+					// dedupers giving a used name fall back to a counter
+					id = SuffixDeduper(op.OperationID, method, path, n)
+				}
+				used[id]++
+				op.OperationID = id
+			})
+		}
+	}
+
+	// generated operationIds never take the ones set explicitly
+	for _, rename := range generated {
+		rename()
+	}
+}
@@ -0,0 +1,140 @@
+package chidoc
+
+import "testing"
+
+func TestLowerCamel(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", ""},
+		{"GetUser", "getUser"},
+		{"getUser", "getUser"},
+		{"HTTPGet", "httpGet"},
+		{"ID", "id"},
+		{"A", "a"},
+		{"Über", "über"},
+	}
+
+	for _, tt := range tests {
+		if got := lowerCamel(tt.name); got != tt.want {
+			t.Errorf("lowerCamel(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMethodPathID(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         string
+	}{
+		{"GET", "/users", "getUsers"},
+		{"get", "/users/{id}", "getUsersId"},
+		{"post", "/static/{wildcard}", "postStaticWildcard"},
+		{"delete", "/", "delete"},
+		{"any", "/v1/items-list", "anyV1ItemsList"},
+	}
+
+	for _, tt := range tests {
+		if got := methodPathID(tt.method, tt.path); got != tt.want {
+			t.Errorf("methodPathID(%q, %q) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAssignOperationIDs(t *testing.T) {
+	generated := func(id string) *Operation {
+		return &Operation{OperationID: id, generatedID: true}
+	}
+
+	tests := []struct {
+		name    string
+		paths   func() Paths
+		deduper OperationIDDeduper
+		want    map[string]map[string]string
+	}{
+		{
+			name: "empty ids by method and path",
+			paths: func() Paths {
+				return Paths{"/users/{id}": {Get: generated(""), Delete: generated("")}}
+			},
+			want: map[string]map[string]string{
+				"/users/{id}": {"get": "getUsersId", "delete": "deleteUsersId"},
+			},
+		},
+		{
+			name: "duplicates get a counter in path and method order",
+			paths: func() Paths {
+				return Paths{
+					"/b": {Get: generated("handler")},
+					"/a": {Get: generated("handler"), Post: generated("handler")},
+				}
+			},
+			want: map[string]map[string]string{
+				"/a": {"get": "handler", "post": "handler2"},
+				"/b": {"get": "handler3"},
+			},
+		},
+		{
+			name: "explicit ids are kept",
+			paths: func() Paths {
+				return Paths{
+					"/a": {Get: generated("list")},
+					"/b": {Get: &Operation{OperationID: "list"}},
+				}
+			},
+			want: map[string]map[string]string{
+				"/a": {"get": "list2"},
+				"/b": {"get": "list"},
+			},
+		},
+		{
+			name:    "method path deduper",
+			deduper: MethodPathDeduper,
+			paths: func() Paths {
+				return Paths{
+					"/a": {Get: generated("list")},
+					"/b": {Get: generated("list")},
+				}
+			},
+			want: map[string]map[string]string{
+				"/a": {"get": "list"},
+				"/b": {"get": "getB"},
+			},
+		},
+		{
+			name: "any method",
+			paths: func() Paths {
+				return Paths{
+					"/a": {Get: generated("main"), Extensions: Extensions{"x-any-method": generated("main")}},
+					"/b": {Extensions: Extensions{"x-any-method": generated("")}},
+				}
+			},
+			want: map[string]map[string]string{
+				"/a": {"get": "main", "any": "main2"},
+				"/b": {"any": "anyB"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		paths := tt.paths()
+		assignOperationIDs(paths, tt.deduper)
+
+		for path, want := range tt.want {
+			ops := paths[path].Operations()
+			if op, isOp := paths[path].Extensions["x-any-method"].(*Operation); isOp {
+				ops["any"] = op
+			}
+
+			if len(ops) != len(want) {
+				t.Errorf("%s: %s has %d operations, want %d", tt.name, path, len(ops), len(want))
+			}
+			for method, id := range want {
+				if op := ops[method]; op == nil || op.OperationID != id {
+					t.Errorf("%s: %s %s operationId = %v, want %q", tt.name, method, path, op, id)
+				}
+			}
+		}
+	}
+}
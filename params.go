@@ -0,0 +1,110 @@
+package chidoc
+
+import (
+	"fmt"
+	"reflect"
+	"unicode"
+)
+
+// paramLocations struct tags naming the parameter of a field by location
+var paramLocations = []string{"path", "query", "header", "cookie"}
+
+// Params adds a parameter for each field of the struct v tagged with its
+// location, the docs tag sets description, required, len and enum
+//
+//	type ListUsersQuery struct {
+//		Page  int    `query:"page" docs:"description:page number"`
+//		Trace string `header:"X-Trace-Id"`
+//	}
+func (b *OperationBuilder) Params(v interface{}) *OperationBuilder {
+	b.paramStructs = append(b.paramStructs, reflect.TypeOf(v))
+	return b
+}
+
+// parseParams expands the tagged fields of a struct to parameters,
+// embedded structs are expanded too
+func parseParams(defs *definitions, t reflect.Type) (params []*Parameter, err error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("params %s is not a struct", t)
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if unicode.IsLower(rune(f.Name[0])) && !f.Anonymous {
+			continue
+		}
+
+		param := paramField(f)
+		if param == nil {
+			if f.Anonymous {
+				inner, err := parseParams(defs, f.Type)
+				if err != nil {
+					return nil, err
+				}
+				params = append(params, inner...)
+			}
+			continue
+		}
+
+		docs := parseDocsTag(f.Tag.Get("docs"))
+		if _, required := docs["required"]; required {
+			param.Required = true
+		}
+
+		if description, exists := docs["description"]; exists {
+			param.Description = description
+		}
+
+		if enum, isEnum := docs["enum"]; isEnum {
+			param.Schema = &Schema{Ref: schemaRef + enum + "Enum"}
+		} else if param.Schema, err = schemaOf(defs, f.Type); err != nil {
+			return nil, err
+		}
+
+		if param.Schema, err = applyDocsTag(defs, param.Schema, docs); err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+
+		// arrays are repeated in query and cookie, comma separated otherwise
+		if param.Schema.Type == "array" {
+			var explode bool = param.In == "query" || param.In == "cookie"
+			param.Style = "simple"
+			if explode {
+				param.Style = "form"
+			}
+			param.Explode = &explode
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// paramField returns the parameter of a field from its location tag,
+// nil for fields without location
+func paramField(f reflect.StructField) *Parameter {
+	for _, in := range paramLocations {
+		tag, exists := f.Tag.Lookup(in)
+		if !exists {
+			continue
+		}
+
+		var name string = parseTag(tag)["name"]
+		switch name {
+		case "-":
+			return nil
+		case "":
+			name = f.Name
+		}
+
+		return &Parameter{
+			In:       in,
+			Name:     name,
+			Required: in == "path",
+		}
+	}
+	return nil
+}
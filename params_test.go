@@ -0,0 +1,72 @@
+package chidoc
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testPaging struct {
+	Page  int `query:"page" docs:"description:page number,min:1"`
+	Limit int `query:"limit"`
+}
+
+type testListParams struct {
+	testPaging
+	ID      string   `path:"id"`
+	Tags    []string `query:"tags"`
+	Fields  []string `header:"X-Fields"`
+	Trace   string   `header:"X-Trace-Id" docs:"required,len:32"`
+	Session string   `cookie:"session"`
+	Kind    string   `query:"kind" docs:"enum:Kind"`
+	Skipped string   `query:"-"`
+	NoTag   string
+	private string `query:"private"`
+}
+
+func TestParseParams(t *testing.T) {
+	explode, noExplode := true, false
+	minimum, length := float64(1), uint64(32)
+
+	want := []*Parameter{
+		{In: "query", Name: "page", Description: "page number", Schema: &Schema{Type: "integer", Minimum: &minimum}},
+		{In: "query", Name: "limit", Schema: &Schema{Type: "integer"}},
+		{In: "path", Name: "id", Required: true, Schema: &Schema{Type: "string"}},
+		{In: "query", Name: "tags", Style: "form", Explode: &explode, Schema: &Schema{Type: "array", Items: &Schema{Type: "string"}}},
+		{In: "header", Name: "X-Fields", Style: "simple", Explode: &noExplode, Schema: &Schema{Type: "array", Items: &Schema{Type: "string"}}},
+		{In: "header", Name: "X-Trace-Id", Required: true, Schema: &Schema{Type: "string", MinLength: &length, MaxLength: &length}},
+		{In: "cookie", Name: "session", Schema: &Schema{Type: "string"}},
+		{In: "query", Name: "kind", Schema: &Schema{Ref: schemaRef + "KindEnum"}},
+	}
+
+	got, err := parseParams(newDefinitions(nil), reflect.TypeOf(&testListParams{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("parseParams returned %d parameters, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("parameter %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseParamsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{"not a struct", 1},
+		{"invalid docs", struct {
+			Page string `query:"page" docs:"min:1"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		if _, err := parseParams(newDefinitions(nil), reflect.TypeOf(tt.v)); err == nil {
+			t.Errorf("%s: parseParams succeeded, want error", tt.name)
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package chidoc
 
 // PathArgType struct to define type of args documentation route
+//
+// Deprecated: use Schema, path parameters are Parameter values
 type PathArgType struct {
 	Kind   string `json:"type"`
 	Format string `json:"format"`
 }
 
 // PathArg struct to define args documentation route
+//
+// Deprecated: use Parameter
 type PathArg struct {
 	Name     string      `json:"name"`
 	In       string      `json:"in"`
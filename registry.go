@@ -0,0 +1,93 @@
+package chidoc
+
+import (
+	"go/ast"
+	"go/build"
+	"path/filepath"
+	"sync"
+)
+
+// docs handler comments extracted at build time, by qualified function name
+var docs = struct {
+	sync.RWMutex
+	comments map[string]string
+	packages map[string]bool
+}{
+	comments: make(map[string]string),
+	packages: make(map[string]bool),
+}
+
+// RegisterDocs registers the handler comments of a package by qualified
+// function name, like github.com/acme/api.Service.GetUser. It's called by
+// the files generated with chidoc gen, so docs don't need the source at
+// runtime
+func RegisterDocs(pkgPath string, comments map[string]string) {
+	docs.Lock()
+	defer docs.Unlock()
+	docs.packages[pkgPath] = true
+	for name, comment := range comments {
+		docs.comments[name] = comment
+	}
+}
+
+// lookupDocs returns the comment registered for fn, registered is true
+// when the package of fn was extracted, even if fn has no comment
+func lookupDocs(fn funcName) (comment string, registered bool) {
+	docs.RLock()
+	defer docs.RUnlock()
+	return docs.comments[fn.String()], docs.packages[fn.pkgPath]
+}
+
+// ExtractDocs returns the comments of the functions and methods declared
+// by the package in dir by qualified function name, with the package name
+// and the path it has at runtime, see RegisterDocs
+func ExtractDocs(dir string) (pkgName, pkgPath string, comments map[string]string, err error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	// main packages are named main at runtime
+	pkgPath = "main"
+	if bp.Name != "main" {
+		if pkgPath, err = modulePath(dir); err != nil {
+			return "", "", nil, err
+		}
+	}
+
+	src := newSources("")
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		file, err := src.parse(filepath.Join(dir, name))
+		if err != nil {
+			return "", "", nil, err
+		}
+		files = append(files, file)
+	}
+
+	comments = make(map[string]string)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			funcDecl, isFunc := decl.(*ast.FuncDecl)
+			if !isFunc || funcDecl.Name.Name == "init" || funcDecl.Name.Name == "_" {
+				continue
+			}
+
+			fn := funcName{
+				pkgPath: pkgPath,
+				recv:    receiverName(funcDecl),
+				name:    funcDecl.Name.Name,
+			}
+
+			// ServeHTTP methods without comment use the type comment
+			group, err := src.funcDoc(files, fn, "", 0)
+			if err != nil {
+				return "", "", nil, err
+			}
+			if group != nil {
+				comments[fn.String()] = group.Text()
+			}
+		}
+	}
+	return bp.Name, pkgPath, comments, nil
+}
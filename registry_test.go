@@ -0,0 +1,49 @@
+package chidoc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractDocs(t *testing.T) {
+	pkgName, pkgPath, comments, err := ExtractDocs("testdata/registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkgName != "registry" || pkgPath != "github.com/n0bode/chidoc/testdata/registry" {
+		t.Errorf("package = %s %s", pkgName, pkgPath)
+	}
+
+	want := map[string]string{
+		pkgPath + ".ListItems": "ListItems lists items\n---\nsummary: list items\n",
+		// ServeHTTP without comment uses the type comment
+		pkgPath + ".Service.ServeHTTP": "Service serves items\n",
+		pkgPath + ".Service.Get":       "Get gets an item\n",
+	}
+	if !reflect.DeepEqual(comments, want) {
+		t.Errorf("comments = %q, want %q", comments, want)
+	}
+}
+
+func TestLookupDocs(t *testing.T) {
+	RegisterDocs("example.com/registered", map[string]string{
+		"example.com/registered.List": "List lists\n",
+	})
+
+	tests := []struct {
+		fn         funcName
+		comment    string
+		registered bool
+	}{
+		{funcName{pkgPath: "example.com/registered", name: "List"}, "List lists\n", true},
+		{funcName{pkgPath: "example.com/registered", name: "Get"}, "", true},
+		{funcName{pkgPath: "example.com/other", name: "List"}, "", false},
+	}
+
+	for _, tt := range tests {
+		comment, registered := lookupDocs(tt.fn)
+		if comment != tt.comment || registered != tt.registered {
+			t.Errorf("lookupDocs(%s) = %q, %v, want %q, %v", tt.fn, comment, registered, tt.comment, tt.registered)
+		}
+	}
+}
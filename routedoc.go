@@ -0,0 +1,193 @@
+package chidoc
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-chi/chi/v5"
+)
+
+// RouteDoc documentation routes added to a router. The spec is generated
+// on the first request, so routes added later are documented, and cached
+// until Refresh
+type RouteDoc struct {
+	root     *chi.Mux
+	settings *DocSettings
+	docpath  string
+	urlDoc   string
+	html     string
+	logo     []byte
+	icon     []byte
+	// hasIcon adds the favicon route
+	hasIcon bool
+	// mainDir source directory of package main, see mainDir
+	mainDir string
+
+	mu    sync.Mutex
+	specs map[string][]byte
+	// err failed generation, returned until Refresh
+	err error
+}
+
+// NewRouteDoc adds documentation routes to root without generating the
+// spec yet
+func NewRouteDoc(root *chi.Mux, docpath string, settings *DocSettings, paths ...string) *RouteDoc {
+	d := newRouteDoc(root, docpath, settings, paths...)
+	d.register()
+	return d
+}
+
+// newRouteDoc reads the documentation pages without adding their routes
+func newRouteDoc(root *chi.Mux, docpath string, settings *DocSettings, paths ...string) *RouteDoc {
+	var urlDoc string = docpath
+
+	for _, path := range paths {
+		urlDoc = joinPath(urlDoc, path)
+	}
+
+	d := &RouteDoc{
+		root:     root,
+		settings: settings,
+		docpath:  docpath,
+		urlDoc:   urlDoc,
+		html:     replaceHTML(htmls[settings.Render], settings.Title, urlDoc, settings),
+		// handlers of package main are found from the goroutine of
+		// main, the spec is generated on a request goroutine
+		mainDir: mainDir(),
+	}
+
+	// set logo swagger
+	settings.Set("info.x-logo.url", joinPath(urlDoc, "logo.png"))
+
+	// Read static logo
+	var logo bytes.Buffer
+	if err := readImage(settings.handlerLogo, &logo); err == nil {
+		d.logo = logo.Bytes()
+	}
+
+	// Read static icon
+	var icon bytes.Buffer
+	if err := readImage(settings.handlerIcon, &icon); err != nil {
+		d.icon = icon.Bytes()
+		d.hasIcon = true
+	}
+	return d
+}
+
+// register adds the documentation routes to root
+func (d *RouteDoc) register() {
+	// Create page index
+	d.root.Get(d.docpath, d.serveIndex)
+
+	if d.logo != nil {
+		d.root.Get(joinPath(d.urlDoc, "logo.png"), d.serveLogo)
+	}
+	if d.hasIcon {
+		d.root.Get(joinPath(d.urlDoc, "favicon.png"), d.serveIcon)
+	}
+
+	// Create route for docs generation
+	d.root.Get(joinPath(d.urlDoc, "docs.yaml"), d.serveSpec)
+	d.root.Get(joinPath(d.urlDoc, "docs.json"), d.serveJSON)
+}
+
+// Refresh generates the spec again from the current routes
+func (d *RouteDoc) Refresh() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.err = d.generate()
+	return d.err
+}
+
+// generate replaces the cached spec, the previous one is kept on error
+func (d *RouteDoc) generate() error {
+	docs, err := genRouteYAML(d.settings, d.root, d.mainDir)
+	if err != nil {
+		return err
+	}
+
+	// JSON is converted from the same YAML output, so both formats match
+	docsJSON, err := yaml.YAMLToJSON([]byte(docs))
+	if err != nil {
+		return err
+	}
+
+	d.specs = map[string][]byte{
+		mimeYAML: []byte(docs),
+		mimeJSON: docsJSON,
+	}
+	return nil
+}
+
+// spec returns the cached spec in mime, generating it the first time.
+// A failed generation is not retried until Refresh
+func (d *RouteDoc) spec(mime string) ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.specs == nil && d.err == nil {
+		d.err = d.generate()
+	}
+
+	// a previous spec is served when Refresh fails
+	if d.specs == nil {
+		return nil, d.err
+	}
+	return d.specs[mime], nil
+}
+
+func (d *RouteDoc) serveIndex(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(d.html))
+}
+
+func (d *RouteDoc) serveLogo(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "image/png")
+	w.Write(d.logo)
+}
+
+func (d *RouteDoc) serveIcon(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "image/png")
+	w.Write(d.icon)
+}
+
+func (d *RouteDoc) serveSpec(w http.ResponseWriter, r *http.Request) {
+	var mime string = negotiateMime(r.Header.Get("Accept"))
+	w.Header().Add("Vary", "Accept")
+	d.write(w, mime)
+}
+
+func (d *RouteDoc) serveJSON(w http.ResponseWriter, r *http.Request) {
+	d.write(w, mimeJSON)
+}
+
+func (d *RouteDoc) write(w http.ResponseWriter, mime string) {
+	spec, err := d.spec(mime)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("Content-Type", mime)
+	w.Write(spec)
+}
+
+// isDocHandler reports whether handler serves the documentation itself,
+// those routes are not documented
+func isDocHandler(handler http.Handler) bool {
+	entry := func(handler http.Handler) uintptr {
+		return handlerFunc(handler).Entry()
+	}
+
+	var d *RouteDoc
+	switch entry(handler) {
+	case entry(http.HandlerFunc(d.serveIndex)),
+		entry(http.HandlerFunc(d.serveLogo)),
+		entry(http.HandlerFunc(d.serveIcon)),
+		entry(http.HandlerFunc(d.serveSpec)),
+		entry(http.HandlerFunc(d.serveJSON)):
+		return true
+	}
+	return false
+}
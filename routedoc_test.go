@@ -0,0 +1,65 @@
+package chidoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// getDoc requests path from r
+func getDoc(r http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestRouteDocRefresh(t *testing.T) {
+	r := chi.NewRouter()
+	d := NewRouteDoc(r, "/docs", NewDocSettings("test", RedocRender))
+
+	// routes added before the first request are documented
+	r.Get("/first", testGetPost)
+	if w := getDoc(r, "/docs/docs.json"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"/first"`) {
+		t.Fatalf("first spec: %d %s", w.Code, w.Body)
+	}
+
+	// the spec is cached until Refresh
+	r.Get("/second", testGetPost)
+	if w := getDoc(r, "/docs/docs.yaml"); strings.Contains(w.Body.String(), "/second") {
+		t.Error("cached spec documents /second")
+	}
+
+	if err := d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if w := getDoc(r, "/docs/docs.yaml"); !strings.Contains(w.Body.String(), "/second") {
+		t.Errorf("refreshed spec doesn't document /second:\n%s", w.Body)
+	}
+	if w := getDoc(r, "/docs/docs.yaml"); strings.Contains(w.Body.String(), "/docs/docs.yaml") {
+		t.Errorf("documentation routes are documented:\n%s", w.Body)
+	}
+}
+
+func TestRouteDocFailedGeneration(t *testing.T) {
+	settings := NewDocSettings("test", RedocRender)
+	settings.SetStrict(true)
+
+	r := chi.NewRouter()
+	r.Get("/undocumented", func(w http.ResponseWriter, r *http.Request) {})
+
+	NewRouteDoc(r, "/lazy", settings)
+	if w := getDoc(r, "/lazy/docs.yaml"); w.Code != http.StatusInternalServerError {
+		t.Errorf("lazy spec: %d %s", w.Code, w.Body)
+	}
+
+	// AddRouteDoc only adds the routes of a generated spec
+	if err := AddRouteDoc(r, "/docs", settings); err == nil {
+		t.Error("AddRouteDoc of an invalid spec succeeded")
+	}
+	if w := getDoc(r, "/docs/docs.yaml"); w.Code != http.StatusNotFound {
+		t.Errorf("failed AddRouteDoc routes: %d %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,103 @@
+package chidoc
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// SchemaNamer names the schema of a named Go type in components/schemas
+// from its package import path and type name, the package of a main
+// package is "main"
+type SchemaNamer func(pkgPath, name string) string
+
+var (
+	// ShortNamer names schemas by type name, like User
+	ShortNamer SchemaNamer = func(pkgPath, name string) string {
+		return schemaName(name)
+	}
+
+	// PackageNamer names schemas by package and type name, like db.User
+	PackageNamer SchemaNamer = func(pkgPath, name string) string {
+		if pkgPath == "" {
+			return schemaName(name)
+		}
+		return schemaName(path.Base(pkgPath) + "." + name)
+	}
+
+	// FullPathNamer names schemas by import path and type name, slashes
+	// become underscores, like github.com_acme_api_db.User
+	FullPathNamer SchemaNamer = func(pkgPath, name string) string {
+		if pkgPath == "" {
+			return schemaName(name)
+		}
+		return schemaName(strings.ReplaceAll(pkgPath, "/", "_") + "." + name)
+	}
+)
+
+// schemaName replaces characters not allowed in component names
+func schemaName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// qualifiedName full name of a type to report it
+func qualifiedName(pkgPath, name string) string {
+	if pkgPath == "" {
+		return name
+	}
+	return pkgPath + "." + name
+}
+
+// definitions models parsed to schemas in a generation
+type definitions struct {
+	namer   SchemaNamer
+	schemas map[string]*Schema
+	types   map[string]string
+	// infer required and nullable fields, see DocSettings.SetInferFields
+	infer bool
+	// allOf composes embedded models, see DocSettings.SetEmbedAllOf
+	allOf bool
+	// implementations of interfaces by interfaceKey
+	implementations map[string]*implementation
+	// mapped schemas of types by typeKey, see DocSettings.MapType
+	mapped map[string]Schema
+	// schemers schemas returned by Schemer types by typeKey
+	schemers map[string]Schema
+}
+
+func newDefinitions(namer SchemaNamer) *definitions {
+	if namer == nil {
+		namer = ShortNamer
+	}
+
+	return &definitions{
+		namer:    namer,
+		schemas:  make(map[string]*Schema),
+		types:    make(map[string]string),
+		schemers: make(map[string]Schema),
+	}
+}
+
+// name returns the schema name of a named type, it fails when the name
+// is already used by another type
+func (d *definitions) name(pkgPath, typeName string) (string, error) {
+	var qualified string = qualifiedName(pkgPath, typeName)
+	var name string = d.namer(pkgPath, typeName)
+	if name == "" {
+		return name, fmt.Errorf("schema namer returned an empty name for %s", qualified)
+	}
+
+	if other, exists := d.types[name]; exists && other != qualified {
+		return name, fmt.Errorf("schema %q is used by %s and %s", name, other, qualified)
+	}
+	d.types[name] = qualified
+	return name, nil
+}
@@ -0,0 +1,82 @@
+package chidoc
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Schemer is implemented by types documenting their own schema, like
+// types with a custom MarshalJSON. It's called on the zero value
+//
+//	func (Money) OpenAPISchema() chidoc.Schema {
+//		return chidoc.Schema{Type: "string", Format: "decimal"}
+//	}
+type Schemer interface {
+	OpenAPISchema() Schema
+}
+
+var schemerType reflect.Type = reflect.TypeOf((*Schemer)(nil)).Elem()
+
+// MapType documents the type t with schema, for types of other packages
+// that can't implement Schemer
+//
+//	docSettings.MapType(reflect.TypeOf(decimal.Decimal{}), chidoc.Schema{Type: "string"})
+func (s *DocSettings) MapType(t reflect.Type, schema Schema) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	s.mapped[typeKey(reflectModel{t})] = schema
+}
+
+// typeKey identifies a type by qualified name, or by its literal when it
+// has no name
+func typeKey(t modelType) string {
+	if t.Name() == "" {
+		return t.String()
+	}
+	return qualifiedName(t.PkgPath(), t.Name())
+}
+
+// customSchema returns the schema set by MapType or Schemer for t, or
+// the builtin schema of standard types. Schemer can't be called on
+// discovered types, their schema is the one seen by reflection
+func customSchema(defs *definitions, t modelType) (schema Schema, exists bool, err error) {
+	var key string = typeKey(t)
+	if schema, exists = defs.mapped[key]; exists {
+		return schema, true, nil
+	}
+
+	if t.Kind() == reflect.Interface {
+		return schema, false, nil
+	}
+	if s, implements := t.Schemer(); implements {
+		if s == nil {
+			if schema, exists = defs.schemers[key]; !exists {
+				return schema, false, fmt.Errorf("%s implements Schemer, add it with SetDefinitions to document it", key)
+			}
+			return schema, true, nil
+		}
+		defs.schemers[key] = *s
+		return *s, true, nil
+	}
+
+	if schema, exists = builtinSchemas[key]; exists {
+		return schema, true, nil
+	}
+
+	// encoding/json encodes byte slices as base64
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		return byteSchema, true, nil
+	}
+	return schema, false, nil
+}
+
+// setCustomSchema sets schema to m, keeping the description of the field
+func setCustomSchema(m *Schema, schema Schema) *Schema {
+	var description string = m.Description
+	*m = schema
+	if m.Description == "" {
+		m.Description = description
+	}
+	return m
+}
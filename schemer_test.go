@@ -0,0 +1,84 @@
+package chidoc
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testMoney int64
+
+func (testMoney) OpenAPISchema() Schema {
+	return Schema{Type: "string", Format: "decimal"}
+}
+
+type testColor struct {
+	R, G, B uint8
+}
+
+func (*testColor) OpenAPISchema() Schema {
+	return Schema{Type: "string", Pattern: "^#[0-9a-f]{6}$"}
+}
+
+type testInvoice struct {
+	Total testMoney  `json:"total" docs:"description:total amount"`
+	Color *testColor `json:"color"`
+	Due   time.Time  `json:"due"`
+}
+
+func TestCustomSchemas(t *testing.T) {
+	settings := NewDocSettings("test", RedocRender)
+	settings.MapType(reflect.TypeOf(&testColor{}), Schema{Type: "string", Format: "color"})
+	settings.MapType(reflect.TypeOf(time.Time{}), Schema{Type: "integer", Format: "unix"})
+
+	defs := newDefinitions(nil)
+	defs.mapped = settings.mapped
+	if _, err := schemaOf(defs, reflect.TypeOf(testInvoice{})); err != nil {
+		t.Fatal(err)
+	}
+
+	// MapType overrides Schemer and builtin schemas
+	want := `{"type":"object","properties":{"color":{"type":"string","format":"color"},` +
+		`"due":{"type":"integer","format":"unix"},` +
+		`"total":{"description":"total amount","type":"string","format":"decimal"}}}`
+	got, err := json.Marshal(defs.schemas["testInvoice"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("testInvoice = %s, want %s", got, want)
+	}
+
+	// pointer receivers implement Schemer too
+	defs = newDefinitions(nil)
+	s, err := schemaOf(defs, reflect.TypeOf(testColor{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Pattern != "^#[0-9a-f]{6}$" {
+		t.Errorf("testColor = %+v", s)
+	}
+}
+
+func TestDiscoveredSchemer(t *testing.T) {
+	pkgs, err := loadPackages([]string{"testdata/schemer"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Schemer can't be called on discovered types
+	if err := discoverDefinitions(newDefinitions(nil), pkgs, refPaths("Price")); err == nil {
+		t.Error("Money was documented without SetDefinitions")
+	}
+
+	// SetDefinitions calls it by reflection
+	defs := newDefinitions(nil)
+	defs.schemers["github.com/n0bode/chidoc/testdata/schemer.Money"] = Schema{Type: "string", Format: "decimal"}
+	if err := discoverDefinitions(defs, pkgs, refPaths("Price")); err != nil {
+		t.Fatal(err)
+	}
+	if amount := defs.schemas["Price"].Properties["amount"]; amount == nil || amount.Format != "decimal" {
+		t.Errorf("amount = %+v", amount)
+	}
+}
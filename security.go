@@ -86,36 +86,57 @@ func NewAuthOAuth(name, url, description string, scopes map[string]string) Auth
 //func NewAuthOAuth2()
 
 // Decode security to opeanapi(YAML) parameters
+//
+// Deprecated: use DecodeScheme
 func (a Auth) Decode(ptr map[string]interface{}) (err error) {
 	if ptr == nil {
 		return errors.New("ptr cannot be nil")
 	}
 
+	schemes := make(map[string]*SecurityScheme)
+	if err = a.DecodeScheme(schemes); err != nil {
+		return err
+	}
+
 	if _, exists := ptr[a.Name]; exists {
 		return errors.New("security already exists")
 	}
+	ptr[a.Name], err = decodeGeneric(schemes[a.Name])
+	return err
+}
 
-	auth := make(map[string]interface{})
-	auth["type"] = a.Type
-	auth["description"] = a.Description
+// DecodeScheme security to opeanapi(YAML) security schemes
+func (a Auth) DecodeScheme(ptr map[string]*SecurityScheme) (err error) {
+	if ptr == nil {
+		return errors.New("ptr cannot be nil")
+	}
+
+	if _, exists := ptr[a.Name]; exists {
+		return errors.New("security already exists")
+	}
+
+	auth := &SecurityScheme{
+		Type:        a.Type,
+		Description: a.Description,
+	}
 
 	switch a.Type {
 	case AuthBasic:
 		break
 	case AuthOAuth2:
-		auth["name"] = a.Name
-		auth["flows"] = map[string]interface{}{
-			"clientCredentials": map[string]interface{}{
-				"tokenUrl": a.UrlAuth,
-				"scopes":   a.Scopes,
+		auth.Name = a.Name
+		auth.Flows = &OAuthFlows{
+			ClientCredentials: &OAuthFlow{
+				TokenURL: a.UrlAuth,
+				Scopes:   a.Scopes,
 			},
 		}
 	case AuthAPIKey:
-		auth["in"] = a.In
-		auth["name"] = a.ParameterName
+		auth.In = a.In
+		auth.Name = a.ParameterName
 	case AuthBearer:
-		auth["scheme"] = "bearer"
-		auth["bearerFormat"] = "jwt"
+		auth.Scheme = "bearer"
+		auth.BearerFormat = "jwt"
 	default:
 		return errors.New("SecurityType invalid")
 	}
@@ -2,5 +2,21 @@ package chidoc
 
 // Server servers
 type Server struct {
-	URL string `json:"url"`
+	URL         string                     `json:"url"`
+	Description string                     `json:"description,omitempty"`
+	Variables   map[string]*ServerVariable `json:"variables,omitempty"`
+	Extensions  Extensions                 `json:"-"`
+}
+
+// MarshalJSON encodes the server with its extensions
+func (s Server) MarshalJSON() ([]byte, error) {
+	type server Server
+	return marshalExtensions(server(s), s.Extensions)
+}
+
+// UnmarshalJSON decodes the server with its extensions
+func (s *Server) UnmarshalJSON(data []byte) (err error) {
+	type server Server
+	s.Extensions, err = unmarshalExtensions(data, (*server)(s))
+	return err
 }
@@ -0,0 +1,182 @@
+package chidoc
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// errNoSource the source of a package could not be found
+var errNoSource = errors.New("source not found")
+
+// sources go files parsed to find handler comments
+type sources struct {
+	fset  *token.FileSet
+	files map[string]*ast.File
+	// mainDir source directory of package main, see mainDir
+	mainDir string
+}
+
+func newSources(mainDir string) *sources {
+	return &sources{
+		fset:    token.NewFileSet(),
+		files:   make(map[string]*ast.File),
+		mainDir: mainDir,
+	}
+}
+
+// parse parses filename once
+func (s *sources) parse(filename string) (*ast.File, error) {
+	if file, exists := s.files[filename]; exists {
+		return file, nil
+	}
+
+	file, err := parser.ParseFile(s.fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	s.files[filename] = file
+	return file, nil
+}
+
+// mainDir returns the source directory of package main from the
+// functions of the call stack, it's empty when called outside of the
+// goroutine of main. Test binaries name the functions of package main by
+// import path, their main is generated
+func mainDir() string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+	for {
+		frame, more := frames.Next()
+		if strings.HasPrefix(frame.Function, "main.") && filepath.Base(frame.File) != "_testmain.go" {
+			return filepath.Dir(frame.File)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// packageDir returns the source directory of a package
+func (s *sources) packageDir(pkgPath string) (string, error) {
+	if pkgPath != "main" {
+		bp, err := build.Import(pkgPath, ".", build.FindOnly)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", errNoSource, err)
+		}
+		return bp.Dir, nil
+	}
+
+	if s.mainDir == "" {
+		return "", fmt.Errorf("%w: package main", errNoSource)
+	}
+	return s.mainDir, nil
+}
+
+// pkg parses the go files of a package
+func (s *sources) pkg(pkgPath string) (files []*ast.File, err error) {
+	dir, err := s.packageDir(pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// files excluded by build tags may declare the same functions
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range bp.GoFiles {
+		file, err := s.parse(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// receiverName returns the type name of a method receiver
+func receiverName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+
+	var expr ast.Expr = decl.Recv.List[0].Type
+	if star, isStar := expr.(*ast.StarExpr); isStar {
+		expr = star.X
+	}
+
+	if ident, isIdent := expr.(*ast.Ident); isIdent {
+		return ident.Name
+	}
+	return ""
+}
+
+// funcDoc returns the comment of the declaration of fn, closures are
+// matched to the declaration containing them by filename and line. For
+// ServeHTTP methods without comment it's the comment of the receiver type
+func (s *sources) funcDoc(files []*ast.File, fn funcName, filename string, line int) (*ast.CommentGroup, error) {
+	var found []*ast.FuncDecl
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			funcDecl, isFunc := decl.(*ast.FuncDecl)
+			if isFunc && funcDecl.Name.Name == fn.name && receiverName(funcDecl) == fn.recv {
+				found = append(found, funcDecl)
+			}
+		}
+	}
+
+	// the same name may be declared in files excluded by build tags
+	if len(found) > 1 {
+		var inside []*ast.FuncDecl
+		for _, funcDecl := range found {
+			start, end := s.fset.Position(funcDecl.Pos()), s.fset.Position(funcDecl.End())
+			if start.Filename == filename && start.Line <= line && line <= end.Line {
+				inside = append(inside, funcDecl)
+			}
+		}
+		if len(inside) > 0 {
+			found = inside
+		}
+	}
+
+	switch {
+	case len(found) > 1:
+		return nil, fmt.Errorf("%s: ambiguous declaration of %s", filename, fn)
+	case len(found) == 1 && found[0].Doc != nil:
+		return found[0].Doc, nil
+	case fn.name != "ServeHTTP":
+		return nil, nil
+	}
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, isGen := decl.(*ast.GenDecl)
+			if !isGen || genDecl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if typeSpec.Name.Name != fn.recv {
+					continue
+				}
+
+				if typeSpec.Doc != nil {
+					return typeSpec.Doc, nil
+				}
+				if len(genDecl.Specs) == 1 {
+					return genDecl.Doc, nil
+				}
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,59 @@
+package chidoc
+
+import (
+	"go/ast"
+	"go/parser"
+	"os/exec"
+	"testing"
+)
+
+func TestFuncDoc(t *testing.T) {
+	src := newSources("")
+	var files []*ast.File
+	for _, f := range []struct{ name, code string }{
+		{"list_a.go", "package p\n\n// List lists on linux\nfunc List() {\n\t_ = func() {}\n}\n"},
+		{"list_b.go", "package p\n\n// List lists on windows\nfunc List() {\n\t_ = func() {}\n}\n\n// Get gets\nfunc Get() {}\n"},
+	} {
+		file, err := parser.ParseFile(src.fset, f.name, f.code, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	tests := []struct {
+		fn       funcName
+		filename string
+		line     int
+		want     string
+		err      bool
+	}{
+		{funcName{pkgPath: "p", name: "List", closure: true}, "list_a.go", 5, "List lists on linux\n", false},
+		{funcName{pkgPath: "p", name: "List", closure: true}, "list_b.go", 5, "List lists on windows\n", false},
+		{funcName{pkgPath: "p", name: "List"}, "<autogenerated>", 1, "", true},
+		{funcName{pkgPath: "p", name: "Get"}, "<autogenerated>", 1, "Get gets\n", false},
+		{funcName{pkgPath: "p", name: "Missing"}, "list_a.go", 1, "", false},
+	}
+
+	for _, tt := range tests {
+		group, err := src.funcDoc(files, tt.fn, tt.filename, tt.line)
+		if (err != nil) != tt.err {
+			t.Errorf("funcDoc(%s, %s) error = %v", tt.fn, tt.filename, err)
+			continue
+		}
+		if got := group.Text(); got != tt.want {
+			t.Errorf("funcDoc(%s, %s) = %q, want %q", tt.fn, tt.filename, got, tt.want)
+		}
+	}
+}
+
+func TestMainPackageUnderTest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+
+	out, err := exec.Command("go", "test", "./testdata/mainpkg").CombinedOutput()
+	if err != nil {
+		t.Errorf("%v\n%s", err, out)
+	}
+}
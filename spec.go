@@ -0,0 +1,46 @@
+package chidoc
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-chi/chi/v5"
+)
+
+// SpecFormat consts to define the encoding of a written spec
+type SpecFormat string
+
+const (
+	// FormatYAML writes the spec as YAML
+	FormatYAML SpecFormat = "yaml"
+	// FormatJSON writes the spec as JSON
+	FormatJSON SpecFormat = "json"
+)
+
+// GenerateSpec builds the OpenAPI document for router without
+// registering any route
+func GenerateSpec(router chi.Routes, settings *DocSettings) (*Document, error) {
+	return genRouteSpec(settings, router, mainDir())
+}
+
+// WriteSpec writes the document to w encoded as format
+func (d *Document) WriteSpec(w io.Writer, format SpecFormat) (err error) {
+	var buffer []byte
+
+	switch format {
+	case FormatYAML:
+		buffer, err = yaml.Marshal(d)
+	case FormatJSON:
+		buffer, err = json.MarshalIndent(d, "", "  ")
+	default:
+		return errors.New("spec format invalid")
+	}
+
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buffer)
+	return err
+}
@@ -0,0 +1,178 @@
+package chidoc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+type testShape interface {
+	Area() float64
+}
+
+type testCircle struct {
+	Kind   string  `json:"kind"`
+	Radius float64 `json:"radius" docs:"min:0"`
+}
+
+func (testCircle) Area() float64 { return 0 }
+
+type testSquare struct {
+	Kind string  `json:"kind"`
+	Side float64 `json:"side"`
+}
+
+func (testSquare) Area() float64 { return 0 }
+
+type testBase struct {
+	ID      int    `json:"id"`
+	Created string `json:"created" docs:"description:Format: ISO 8601"`
+}
+
+type testUser struct {
+	testBase
+	Name   string            `json:"name" docs:"required,len:2-64"`
+	Email  *string           `json:"email,omitempty" docs:"format:email"`
+	Labels map[string]string `json:"labels" docs:"key:label value"`
+	Scores map[int]float64   `json:"scores"`
+}
+
+type testPost struct {
+	Title  string      `json:"title" docs:"example:'Hello, world'"`
+	Author *testUser   `json:"author" docs:"deprecated,description:the author"`
+	Editor testUser    `json:"editor"`
+	Shapes []testShape `json:"shapes"`
+	Status string      `json:"status" docs:"enum:Status,example:draft"`
+}
+
+type testCreatePost struct {
+	Title string `json:"title" docs:"required"`
+}
+
+// testListPosts lists the posts
+// ---
+// summary: list posts
+// tags: [posts]
+// responses: {"200": {description: OK, content: {application/json: {schema: {type: array, items: {$ref: '#/components/schemas/testPost'}}}}}}
+func testListPosts(w http.ResponseWriter, r *http.Request) {}
+
+// testCreatePostHandler creates a post
+// ---
+// summary: create a post
+func testCreatePostHandler(ctx context.Context, req testCreatePost) (*testPost, error) {
+	return nil, nil
+}
+
+type testService struct{}
+
+// testService serves posts
+// ---
+// summary: service
+// responses: {"204": {description: served}}
+func (testService) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+// testGetPost gets a post
+func testGetPost(w http.ResponseWriter, r *http.Request) {}
+
+func testRouter() *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/posts", testListPosts)
+	r.Method(http.MethodPost, "/posts", JSON(testCreatePostHandler))
+	r.Get("/posts/{id:[0-9]+}", testGetPost)
+	r.Delete("/posts/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {})
+	r.Get("/lang/{code:[a-z]{2}}", testGetPost)
+	r.Handle("/service", testService{})
+	r.Route("/users", func(r chi.Router) {
+		r.Get("/{id}", testGetPost)
+		r.Put("/{id}", testGetPost)
+	})
+	r.Get("/static/*", testGetPost)
+
+	// builders are global, a second test run would add the params again
+	testOnce.Do(func() {
+		Op(testGetPost).Summary("get").Params(struct {
+			Fields []string `query:"fields"`
+		}{}).Response(http.StatusOK, testUser{})
+	})
+	return r
+}
+
+var testOnce sync.Once
+
+func testSettings() *DocSettings {
+	settings := NewDocSettings("test", RedocRender)
+	// enums are registered before the models using them
+	settings.SetDefinitions(testPost{}, Enum("Status", "post status", "draft", "published"))
+	settings.RegisterImplementations((*testShape)(nil), testCircle{}, testSquare{})
+	settings.SetDiscriminator((*testShape)(nil), "kind")
+	settings.SetInferFields(true)
+	return settings
+}
+
+func TestGoldenSpec(t *testing.T) {
+	var golden string = filepath.Join("testdata", "spec.golden.yaml")
+	r := testRouter()
+
+	var first []byte
+	for i := 0; i < 20; i++ {
+		doc, err := GenerateSpec(r, testSettings())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var spec bytes.Buffer
+		if err := doc.WriteSpec(&spec, FormatYAML); err != nil {
+			t.Fatal(err)
+		}
+
+		if first == nil {
+			first = spec.Bytes()
+			continue
+		}
+		if !bytes.Equal(first, spec.Bytes()) {
+			t.Fatalf("generation %d differs from the first:\n%s", i, spec.Bytes())
+		}
+	}
+
+	if *update {
+		if err := os.WriteFile(golden, first, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, want) {
+		t.Errorf("spec differs from %s, run go test -update to accept it:\n%s", golden, first)
+	}
+}
+
+func TestGoldenSpecValid(t *testing.T) {
+	doc, err := GenerateSpec(testRouter(), testSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// undocumented handlers have no responses
+	var want = []string{"DELETE /posts/{id}: operation without responses"}
+	var verr *ValidationError
+	if err := Validate(doc); !errors.As(err, &verr) || !reflect.DeepEqual(verr.Problems, want) {
+		t.Errorf("Validate() = %v, want %q", err, want)
+	}
+	if reflect.DeepEqual(doc.Paths, Paths{}) {
+		t.Error("no paths documented")
+	}
+}
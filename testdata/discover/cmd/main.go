@@ -0,0 +1,10 @@
+package main
+
+import "github.com/n0bode/chidoc/testdata/discover"
+
+// Order is declared by a main package
+type Order struct {
+	Items []discover.Item `json:"items"`
+}
+
+func main() {}
@@ -0,0 +1,17 @@
+package discover
+
+// Item is found by discovery
+type Item struct {
+	ID    int     `json:"id"`
+	Tags  []Tag   `json:"tags"`
+	Price float64 `json:"price,omitempty"`
+}
+
+// Tag is referenced by Item
+type Tag struct {
+	Name string `json:"name"`
+}
+
+type hidden struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type api struct{}
+
+// users lists users
+// ---
+// summary: list users
+func (api) users(w http.ResponseWriter, r *http.Request) {}
+
+func routes() *chi.Mux {
+	var a api
+	r := chi.NewRouter()
+	r.Get("/users", a.users)
+	return r
+}
+
+func main() {
+	http.ListenAndServe(":8080", routes())
+}
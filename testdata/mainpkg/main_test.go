@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/n0bode/chidoc"
+)
+
+// test binaries name the functions of package main by import path, their
+// source is found without the directory of main
+func TestSpec(t *testing.T) {
+	doc, err := chidoc.GenerateSpec(routes(), chidoc.NewDocSettings("test", chidoc.RedocRender))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary := doc.Paths["/users"].Get.Summary; summary != "list users" {
+		t.Errorf("summary = %q", summary)
+	}
+}
@@ -0,0 +1,18 @@
+package registry
+
+import "net/http"
+
+// ListItems lists items
+// ---
+// summary: list items
+func ListItems(w http.ResponseWriter, r *http.Request) {}
+
+func undocumented(w http.ResponseWriter, r *http.Request) {}
+
+// Service serves items
+type Service struct{}
+
+func (Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+// Get gets an item
+func (s *Service) Get(w http.ResponseWriter, r *http.Request) {}
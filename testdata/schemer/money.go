@@ -0,0 +1,15 @@
+package schemer
+
+import "github.com/n0bode/chidoc"
+
+// Money amounts are encoded as decimal strings
+type Money int64
+
+func (Money) OpenAPISchema() chidoc.Schema {
+	return chidoc.Schema{Type: "string", Format: "decimal"}
+}
+
+// Price is discovered with its Money field
+type Price struct {
+	Amount Money `json:"amount"`
+}
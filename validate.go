@@ -0,0 +1,343 @@
+package chidoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemaTypes types allowed on a schema by OpenAPI 3.0
+var schemaTypes = map[string]bool{
+	"integer": true,
+	"number":  true,
+	"string":  true,
+	"boolean": true,
+	"array":   true,
+	"object":  true,
+}
+
+// templateParam matches {param} in a path template
+var templateParam = regexp.MustCompile(`{([^{}]+)}`)
+
+// ValidationError lists the problems found in a document
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid openapi document:\n\t" + strings.Join(e.Problems, "\n\t")
+}
+
+// Validate checks doc against the OpenAPI 3.0 rules, it returns a
+// *ValidationError listing every problem found
+func Validate(doc *Document) error {
+	v := &validator{doc: doc}
+
+	if err := v.refs(); err != nil {
+		return err
+	}
+	v.paths()
+	v.schemas()
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	sort.Strings(v.problems)
+	return &ValidationError{Problems: v.problems}
+}
+
+type validator struct {
+	doc      *Document
+	problems []string
+}
+
+func (v *validator) report(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// refs checks every local $ref resolves inside the document
+func (v *validator) refs() error {
+	root, err := decodeGeneric(v.doc)
+	if err != nil {
+		return err
+	}
+
+	walkRefs("#", root, func(location, ref string) {
+		if !resolvePointer(root, ref) {
+			v.report("%s: unresolved $ref %q", location, ref)
+		}
+	})
+	return nil
+}
+
+// decodeGeneric converts v to its JSON form of maps and slices
+func decodeGeneric(v interface{}) (root interface{}, err error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &root)
+	return root, err
+}
+
+// walkRefs calls fn for every $ref found in node
+func walkRefs(location string, node interface{}, fn func(location, ref string)) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			ref, isRef := child.(string)
+			if key == "$ref" && isRef {
+				fn(location, ref)
+				continue
+			}
+			walkRefs(location+"/"+escapePointer(key), child, fn)
+		}
+	case []interface{}:
+		for i, child := range value {
+			walkRefs(fmt.Sprintf("%s/%d", location, i), child, fn)
+		}
+	}
+}
+
+// paths checks path templates and operation ids
+func (v *validator) paths() {
+	operationIDs := make(map[string]string)
+
+	// sorted, duplicated operationIds are reported the same way every time
+	for _, path := range sortedKeys(v.doc.Paths) {
+		var item *PathItem = v.doc.Paths[path]
+		if item == nil {
+			continue
+		}
+
+		var template = make(map[string]bool)
+		for _, match := range templateParam.FindAllStringSubmatch(path, -1) {
+			template[match[1]] = true
+		}
+
+		ops := operations(item)
+		for _, method := range sortedKeys(ops) {
+			var op *Operation = ops[method]
+			var location string = strings.ToUpper(method) + " " + path
+
+			if len(op.Responses) == 0 {
+				v.report("%s: operation without responses", location)
+			}
+			if op.OperationID != "" {
+				if other, exists := operationIDs[op.OperationID]; exists {
+					v.report("%s: operationId %q already used by %s", location, op.OperationID, other)
+				} else {
+					operationIDs[op.OperationID] = location
+				}
+			}
+
+			declared := make(map[string]bool)
+			for _, param := range append(item.Parameters, op.Parameters...) {
+				param = v.parameter(param)
+				if param == nil || param.In != "path" {
+					continue
+				}
+				declared[param.Name] = true
+
+				if !template[param.Name] {
+					v.report("%s: path parameter %q is not in the path template", location, param.Name)
+				}
+				if !param.Required {
+					v.report("%s: path parameter %q must be required", location, param.Name)
+				}
+			}
+
+			for name := range template {
+				if !declared[name] {
+					v.report("%s: path template {%s} has no matching parameter", location, name)
+				}
+			}
+		}
+	}
+}
+
+// operations returns the operations of item with the one documented in
+// the x-any-method extension
+func operations(item *PathItem) map[string]*Operation {
+	ops := item.Operations()
+	switch ext := item.Extensions["x-any-method"].(type) {
+	case nil:
+	case *Operation:
+		ops["x-any-method"] = ext
+	default:
+		// extensions of decoded documents are generic values
+		var op Operation
+		if data, err := json.Marshal(ext); err == nil && json.Unmarshal(data, &op) == nil {
+			ops["x-any-method"] = &op
+		}
+	}
+	return ops
+}
+
+// parameter resolves a parameter reference from components
+func (v *validator) parameter(param *Parameter) *Parameter {
+	if param == nil || param.Ref == "" {
+		return param
+	}
+
+	const prefix = "#/components/parameters/"
+	if v.doc.Components == nil || !strings.HasPrefix(param.Ref, prefix) {
+		return nil
+	}
+	return v.doc.Components.Parameters[strings.TrimPrefix(param.Ref, prefix)]
+}
+
+// schemas checks type values of every schema in the document
+func (v *validator) schemas() {
+	visited := make(map[*Schema]bool)
+
+	var check func(location string, s *Schema)
+	check = func(location string, s *Schema) {
+		if s == nil || visited[s] {
+			return
+		}
+		visited[s] = true
+
+		if s.Type != "" && !schemaTypes[s.Type] {
+			v.report("%s: invalid schema type %q", location, s.Type)
+		}
+		if s.Type == "array" && s.Items == nil && s.Ref == "" {
+			v.report("%s: array schema without items", location)
+		}
+
+		check(location+"/items", s.Items)
+		check(location+"/not", s.Not)
+		for name, prop := range s.Properties {
+			check(location+"/properties/"+escapePointer(name), prop)
+		}
+		if s.AdditionalProperties != nil {
+			check(location+"/additionalProperties", s.AdditionalProperties.Schema)
+		}
+		for i, inner := range s.AllOf {
+			check(fmt.Sprintf("%s/allOf/%d", location, i), inner)
+		}
+		for i, inner := range s.OneOf {
+			check(fmt.Sprintf("%s/oneOf/%d", location, i), inner)
+		}
+		for i, inner := range s.AnyOf {
+			check(fmt.Sprintf("%s/anyOf/%d", location, i), inner)
+		}
+	}
+
+	content := func(location string, content map[string]*MediaType) {
+		for mime, media := range content {
+			if media != nil {
+				check(location+"/content/"+escapePointer(mime)+"/schema", media.Schema)
+			}
+		}
+	}
+
+	parameter := func(location string, p *Parameter) {
+		if p == nil {
+			v.report("%s: null parameter", location)
+			return
+		}
+		check(location+"/schema", p.Schema)
+		content(location, p.Content)
+	}
+
+	response := func(location string, r *Response) {
+		if r == nil {
+			v.report("%s: null response", location)
+			return
+		}
+		content(location, r.Content)
+		for name, h := range r.Headers {
+			if h == nil {
+				v.report("%s/headers/%s: null header", location, escapePointer(name))
+				continue
+			}
+			check(location+"/headers/"+escapePointer(name)+"/schema", h.Schema)
+		}
+	}
+
+	if c := v.doc.Components; c != nil {
+		for name, s := range c.Schemas {
+			check("#/components/schemas/"+escapePointer(name), s)
+		}
+		for name, p := range c.Parameters {
+			parameter("#/components/parameters/"+escapePointer(name), p)
+		}
+		for name, r := range c.Responses {
+			response("#/components/responses/"+escapePointer(name), r)
+		}
+		for name, b := range c.RequestBodies {
+			if b == nil {
+				v.report("#/components/requestBodies/%s: null request body", escapePointer(name))
+				continue
+			}
+			content("#/components/requestBodies/"+escapePointer(name), b.Content)
+		}
+	}
+
+	for path, item := range v.doc.Paths {
+		var location string = "#/paths/" + escapePointer(path)
+		if item == nil {
+			v.report("%s: null path item", location)
+			continue
+		}
+		for i, p := range item.Parameters {
+			parameter(fmt.Sprintf("%s/parameters/%d", location, i), p)
+		}
+
+		for method, op := range operations(item) {
+			var opLocation string = location + "/" + escapePointer(method)
+			for i, p := range op.Parameters {
+				parameter(fmt.Sprintf("%s/parameters/%d", opLocation, i), p)
+			}
+			if op.RequestBody != nil {
+				content(opLocation+"/requestBody", op.RequestBody.Content)
+			}
+			for status, r := range op.Responses {
+				response(opLocation+"/responses/"+escapePointer(status), r)
+			}
+		}
+	}
+}
+
+// escapePointer escapes a JSON pointer token
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// resolvePointer checks a local reference exists in root, external
+// references are not checked
+func resolvePointer(root interface{}, ref string) bool {
+	if !strings.HasPrefix(ref, "#") {
+		return true
+	}
+
+	var node interface{} = root
+	for _, token := range strings.Split(strings.TrimPrefix(ref[1:], "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+
+		switch value := node.(type) {
+		case map[string]interface{}:
+			child, exists := value[token]
+			if !exists {
+				return false
+			}
+			node = child
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(value) {
+				return false
+			}
+			node = value[i]
+		default:
+			return false
+		}
+	}
+	return true
+}
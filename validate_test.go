@@ -0,0 +1,165 @@
+package chidoc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	ok := func() Responses {
+		return Responses{"200": {Description: "ok"}}
+	}
+
+	tests := []struct {
+		name string
+		doc  *Document
+		want []string
+	}{
+		{
+			name: "valid",
+			doc: &Document{Paths: Paths{
+				"/users/{id}": {Get: &Operation{
+					Parameters: []*Parameter{{In: "path", Name: "id", Required: true, Schema: &Schema{Type: "string"}}},
+					Responses:  ok(),
+				}},
+			}},
+		},
+		{
+			name: "null parameters",
+			doc: &Document{
+				Paths: Paths{
+					"/a": {Parameters: []*Parameter{nil}, Get: &Operation{Parameters: []*Parameter{nil}, Responses: ok()}},
+				},
+				Components: &Components{Parameters: map[string]*Parameter{"p": nil}},
+			},
+			want: []string{
+				"#/components/parameters/p: null parameter",
+				"#/paths/~1a/get/parameters/0: null parameter",
+				"#/paths/~1a/parameters/0: null parameter",
+			},
+		},
+		{
+			name: "null responses and headers",
+			doc: &Document{Paths: Paths{
+				"/a": {Get: &Operation{Responses: Responses{
+					"200": nil,
+					"204": {Description: "ok", Headers: map[string]*Header{"X-Id": nil}},
+				}}},
+			}},
+			want: []string{
+				"#/paths/~1a/get/responses/200: null response",
+				"#/paths/~1a/get/responses/204/headers/X-Id: null header",
+			},
+		},
+		{
+			name: "missing responses",
+			doc: &Document{Paths: Paths{
+				"/a": {Post: &Operation{}},
+			}},
+			want: []string{"POST /a: operation without responses"},
+		},
+		{
+			name: "any method",
+			doc: &Document{Paths: Paths{
+				"/a": {
+					Get:        &Operation{OperationID: "a", Responses: ok()},
+					Extensions: Extensions{"x-any-method": &Operation{OperationID: "a"}},
+				},
+			}},
+			want: []string{
+				"X-ANY-METHOD /a: operation without responses",
+				"X-ANY-METHOD /a: operationId \"a\" already used by GET /a",
+			},
+		},
+		{
+			name: "path parameters",
+			doc: &Document{Paths: Paths{
+				"/users/{id}": {Get: &Operation{
+					Parameters: []*Parameter{{In: "path", Name: "user", Schema: &Schema{Type: "str"}}},
+					Responses:  ok(),
+				}},
+			}},
+			want: []string{
+				"#/paths/~1users~1{id}/get/parameters/0/schema: invalid schema type \"str\"",
+				"GET /users/{id}: path parameter \"user\" is not in the path template",
+				"GET /users/{id}: path parameter \"user\" must be required",
+				"GET /users/{id}: path template {id} has no matching parameter",
+			},
+		},
+		{
+			name: "array references",
+			doc: &Document{Paths: Paths{
+				"/a": {
+					Get: &Operation{
+						Parameters: []*Parameter{{In: "query", Name: "q"}},
+						Responses:  ok(),
+					},
+					Post: &Operation{
+						Parameters: []*Parameter{
+							{Ref: "#/paths/~1a/get/parameters/0"},
+							{Ref: "#/paths/~1a/get/parameters/1"},
+							{Ref: "#/paths/~1a/get/parameters/x"},
+						},
+						Responses: ok(),
+					},
+				},
+			}},
+			want: []string{
+				"#/paths/~1a/post/parameters/1: unresolved $ref \"#/paths/~1a/get/parameters/1\"",
+				"#/paths/~1a/post/parameters/2: unresolved $ref \"#/paths/~1a/get/parameters/x\"",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		err := Validate(tt.doc)
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s: Validate() = %v", tt.name, err)
+			}
+			continue
+		}
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("%s: Validate() = %v, want %q", tt.name, err, tt.want)
+			continue
+		}
+		if !reflect.DeepEqual(verr.Problems, tt.want) {
+			t.Errorf("%s: problems = %q, want %q", tt.name, verr.Problems, tt.want)
+		}
+	}
+}
+
+func TestResolvePointer(t *testing.T) {
+	root, err := decodeGeneric(map[string]interface{}{
+		"a/b": map[string]interface{}{"list": []string{"x", "y"}},
+		"c~d": 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{"#", true},
+		{"#/a~1b", true},
+		{"#/a~1b/list/1", true},
+		{"#/a~1b/list/2", false},
+		{"#/a~1b/list/-1", false},
+		{"#/a~1b/list/first", false},
+		{"#/c~0d", true},
+		{"#/c~0d/e", false},
+		{"#/missing", false},
+		{"other.yaml#/missing", true},
+	}
+
+	for _, tt := range tests {
+		if got := resolvePointer(root, tt.ref); got != tt.want {
+			t.Errorf("resolvePointer(%q) = %v, want %v", tt.ref, got, tt.want)
+		}
+	}
+}